@@ -56,9 +56,13 @@ TOKEN_PICKLE_FILE = (
     or os.path.join(SCRIPT_DIR, "token.pickle")
 )
 
-# This OAuth 2.0 access scope allows an application to upload files to the
-# authenticated user's YouTube channel, but doesn't allow other types of access.
-SCOPES = ['https://www.googleapis.com/auth/youtube.upload']
+# youtube.upload allows uploading videos; youtube.force-ssl is needed to attach caption tracks.
+# Tokens created before captions were added lack force-ssl: caption uploads then fail (the video
+# upload still succeeds) until the token is recreated.
+SCOPES = [
+    'https://www.googleapis.com/auth/youtube.upload',
+    'https://www.googleapis.com/auth/youtube.force-ssl',
+]
 YOUTUBE_API_SERVICE_NAME = "youtube"
 YOUTUBE_API_VERSION = "v3"
 
@@ -197,7 +201,26 @@ def initialize_upload(youtube, options):
         media_body=MediaFileUpload(options.file, chunksize=-1, resumable=True)
     )
 
-    resumable_upload(insert_request)
+    video_id = resumable_upload(insert_request)
+    upload_captions(youtube, video_id, options.caption or [])
+
+
+def upload_captions(youtube, video_id, captions):
+    # Each caption is "lang:path". Failures are reported but never fail the video upload.
+    for spec in captions:
+        lang, sep, path = spec.partition(":")
+        if not sep or not lang or not os.path.exists(path):
+            print("Skipping invalid caption spec %r" % spec)
+            continue
+        try:
+            response = youtube.captions().insert(
+                part="snippet",
+                body=dict(snippet=dict(videoId=video_id, language=lang, name="", isDraft=False)),
+                media_body=MediaFileUpload(path, mimetype="application/octet-stream", resumable=False),
+            ).execute()
+            print("Caption id '%s' (%s) was successfully uploaded." % (response.get("id", ""), lang))
+        except HttpError as e:
+            print("Caption upload for %s failed with HTTP error %d:\n%s" % (lang, e.resp.status, e.content))
 
 
 # This method implements an exponential backoff strategy to resume a
@@ -213,6 +236,7 @@ def resumable_upload(insert_request):
             if response is not None:
                 if 'id' in response:
                     print("Video id '%s' was successfully uploaded." % response['id'])
+                    return response['id']
                 else:
                     exit("The upload failed with an unexpected response: %s" % response)
         except HttpError as e:
@@ -248,6 +272,8 @@ if __name__ == '__main__':
                         default="")
     parser.add_argument("--privacyStatus", choices=VALID_PRIVACY_STATUSES,
                         default=VALID_PRIVACY_STATUSES[0], help="Video privacy status.")
+    parser.add_argument("--caption", action="append",
+                        help="Caption track to attach after upload, as lang:path (repeatable)")
     args = parser.parse_args()
 
     if not os.path.exists(args.file):
@@ -0,0 +1,16 @@
+-- Embeddings of content text (title + narration) for semantic duplicate detection.
+-- Vectors are plain REAL[] so no extension (pgvector) is required; similarity is computed in
+-- the manager. One row per content and embedding model.
+
+CREATE TABLE IF NOT EXISTS content_embeddings (
+  content_id BIGINT NOT NULL REFERENCES contents(id) ON DELETE CASCADE,
+  model TEXT NOT NULL,
+  text_sha256 TEXT NOT NULL,
+  embedding REAL[] NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (content_id, model)
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_embeddings_model
+  ON content_embeddings(model);
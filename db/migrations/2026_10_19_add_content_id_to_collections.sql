@@ -0,0 +1,5 @@
+-- Content generated from a collection (Collection:GenerateContent). The content links back in
+-- contents.meta.source.collection_id.
+
+ALTER TABLE public.collections
+  ADD COLUMN IF NOT EXISTS content_id BIGINT NULL REFERENCES public.contents(id) ON DELETE SET NULL;
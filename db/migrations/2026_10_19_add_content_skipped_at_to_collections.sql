@@ -0,0 +1,6 @@
+-- Collection:GenerateContent selects collections without content_id; articles it skips (no
+-- readable text, or too similar to existing content) are marked here so they are not retried.
+-- processed_at stays owned by Subject:ProcessCollections.
+
+ALTER TABLE public.collections
+  ADD COLUMN IF NOT EXISTS content_skipped_at TIMESTAMP NULL;
@@ -0,0 +1,10 @@
+-- Rss:FetchHtml sends conditional GETs (etag/last_modified from the previous response) and records
+-- failures per feed; a feed failing failure_count >= [rss] max_failures times in a row is
+-- deactivated.
+
+ALTER TABLE public.subscriptions
+  ADD COLUMN IF NOT EXISTS etag VARCHAR NULL,
+  ADD COLUMN IF NOT EXISTS last_modified VARCHAR NULL,
+  ADD COLUMN IF NOT EXISTS last_error TEXT NULL,
+  ADD COLUMN IF NOT EXISTS last_error_at TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS failure_count INTEGER NOT NULL DEFAULT 0;
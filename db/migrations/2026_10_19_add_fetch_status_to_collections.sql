@@ -0,0 +1,9 @@
+-- Outcome of the last article fetch (internal/fetcher): fetch_status is ok, disallowed, too_large,
+-- content_type, http_error or error. Failed articles are stored with an empty html_content so
+-- Rss:FetchHtml can retry them up to [rss] item_max_attempts times.
+
+ALTER TABLE public.collections
+  ADD COLUMN IF NOT EXISTS fetch_status VARCHAR(20) NULL,
+  ADD COLUMN IF NOT EXISTS fetch_error TEXT NULL,
+  ADD COLUMN IF NOT EXISTS http_status INTEGER NULL,
+  ADD COLUMN IF NOT EXISTS fetch_attempts INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+-- When a subject was last extracted from a fetched article (Subject:ProcessCollections); weighted
+-- subject selection favours subjects that are still in the news.
+
+ALTER TABLE public.subjects
+  ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP NULL;
+
+UPDATE public.subjects SET last_seen_at = created_at WHERE last_seen_at IS NULL;
@@ -0,0 +1,8 @@
+-- Performance of a subject's published videos relative to the channel (1 = typical), rolled up
+-- from contents meta view/like/comment counts by Youtube:UpdateMeta. NULL until a video is old
+-- enough to count.
+
+ALTER TABLE public.subjects
+  ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION NULL,
+  ADD COLUMN IF NOT EXISTS score_videos INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS score_updated_at TIMESTAMP NULL;
@@ -0,0 +1,7 @@
+-- Readable article text extracted from html_content during Rss:FetchHtml, so downstream steps
+-- (subjects, scripts) do not send raw HTML to the LLM.
+
+ALTER TABLE public.collections
+  ADD COLUMN IF NOT EXISTS text_content TEXT NULL,
+  ADD COLUMN IF NOT EXISTS byline VARCHAR NULL,
+  ADD COLUMN IF NOT EXISTS published_at TIMESTAMP NULL;
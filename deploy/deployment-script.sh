@@ -165,10 +165,10 @@ case "${host}" in
       link_system_service "$f"
     done
     reload_systemd_system
-    enable_now_system generate_wav.service generate_srt.service generate_mp3.service
+    enable_now_system review_fun_fact.service generate_wav.service generate_srt.service generate_mp3.service
     # Keep laravel/other services untouched unless already running.
     try_restart_system ai_generate_fun_facts.service
-    try_restart_system generate_wav.service generate_srt.service generate_mp3.service
+    try_restart_system review_fun_fact.service generate_wav.service generate_srt.service generate_mp3.service
     ;;
 
   pinky)
@@ -181,9 +181,9 @@ case "${host}" in
       link_system_service "$f"
     done
     reload_systemd_system
-    enable_now_system generate_wav.service generate_srt.service generate_mp3.service
+    enable_now_system review_fun_fact.service generate_wav.service generate_srt.service generate_mp3.service
     try_restart_system gemini_generate_fun_facts.service
-    try_restart_system generate_wav.service generate_srt.service generate_mp3.service
+    try_restart_system review_fun_fact.service generate_wav.service generate_srt.service generate_mp3.service
     ;;
 
   legion)
@@ -196,8 +196,8 @@ case "${host}" in
       link_system_service "$f"
     done
     reload_systemd_system
-    enable_now_system generate_wav.service generate_srt.service generate_mp3.service
-    try_restart_system generate_wav.service generate_srt.service generate_mp3.service
+    enable_now_system review_fun_fact.service generate_wav.service generate_srt.service generate_mp3.service
+    try_restart_system review_fun_fact.service generate_wav.service generate_srt.service generate_mp3.service
     ;;
 
   devbox)
@@ -212,7 +212,7 @@ case "${host}" in
     reload_systemd_system
     # Devbox historically didn't auto-enable these; only try-restart if already running.
     try_restart_system gemini_generate_fun_facts.service
-    try_restart_system generate_wav.service generate_srt.service generate_mp3.service
+    try_restart_system review_fun_fact.service generate_wav.service generate_srt.service generate_mp3.service
     ;;
 
   *)
@@ -99,10 +99,14 @@ func Run(args []string) int {
 		runErr = runCheckYoutubeIsUploadable(ctx, jctx, cmdArgs)
 	case "Check:SrtIsGenerated":
 		runErr = runCheckSrtIsGenerated(ctx, jctx, cmdArgs)
+	case "Check:SubtitlesValid":
+		runErr = runCheckSubtitlesValid(ctx, jctx, cmdArgs)
 	case "Check:WavIsGenerated":
 		runErr = runCheckWavIsGenerated(ctx, jctx, cmdArgs)
 	case "Content:FindDuplicateTitles":
 		runErr = runContentFindDuplicateTitles(ctx, jctx, cmdArgs)
+	case "Content:FindSimilar":
+		runErr = runContentFindSimilar(ctx, jctx, cmdArgs)
 	case "Content:IdentifySubject":
 		runErr = runContentIdentifySubject(ctx, jctx, cmdArgs)
 	case "Content:Reset":
@@ -135,18 +139,50 @@ func Run(args []string) int {
 		runErr = runFixSubtitles(ctx, jctx, cmdArgs)
 	case "job:CorrectSubtitles":
 		runErr = runCorrectSubtitles(ctx, jctx, cmdArgs)
+	case "job:ReviewFunFact":
+		runErr = runReviewFunFact(ctx, jctx, cmdArgs)
+	case "job:TranslateSubtitles":
+		runErr = runTranslateSubtitles(ctx, jctx, cmdArgs)
 	case "job:UploadPodcastToTikTok":
 		runErr = runUploadTikTok(ctx, jctx, cmdArgs)
 	case "job:UploadPodcastToYoutube":
 		runErr = runUploadYouTube(ctx, jctx, cmdArgs)
+	case "Prompt:List":
+		runErr = runPromptList(ctx, jctx, cmdArgs)
+	case "Prompt:Render":
+		runErr = runPromptRender(ctx, jctx, cmdArgs)
 	case "Rss:FetchHtml":
 		runErr = runRssFetchHtml(ctx, jctx, cmdArgs)
 	case "Rss:Subscribe":
 		runErr = runRssSubscribe(ctx, jctx, cmdArgs)
+	case "Rss:List":
+		runErr = runRssList(ctx, jctx, cmdArgs)
+	case "Rss:Pause":
+		runErr = runRssPause(ctx, jctx, cmdArgs)
+	case "Rss:Resume":
+		runErr = runRssResume(ctx, jctx, cmdArgs)
+	case "Rss:Remove":
+		runErr = runRssRemove(ctx, jctx, cmdArgs)
+	case "Rss:Import":
+		runErr = runRssImport(ctx, jctx, cmdArgs)
+	case "Rss:Export":
+		runErr = runRssExport(ctx, jctx, cmdArgs)
+	case "Collection:GenerateContent":
+		runErr = runCollectionGenerateContent(ctx, jctx, cmdArgs)
 	case "Subject:ProcessCollections":
 		runErr = runSubjectProcessCollections(ctx, jctx, cmdArgs)
+	case "Subject:List":
+		runErr = runSubjectList(ctx, jctx, cmdArgs)
+	case "Subject:Activate":
+		runErr = runSubjectActivate(ctx, jctx, cmdArgs)
+	case "Subject:Deactivate":
+		runErr = runSubjectDeactivate(ctx, jctx, cmdArgs)
+	case "Subject:Add":
+		runErr = runSubjectAdd(ctx, jctx, cmdArgs)
 	case "Youtube:UpdateMeta":
 		runErr = runYoutubeUpdateMeta(ctx, jctx, cmdArgs)
+	case "Youtube:Auth":
+		runErr = runYoutubeAuth(ctx, jctx, cmdArgs)
 	case "app:fabric-extract-wisdom":
 		runErr = runAppFabricExtractWisdom(ctx, jctx, cmdArgs)
 	case "chat:HiennaGPT":
@@ -159,6 +195,8 @@ func Run(args []string) int {
 		runErr = runTTSSplitJobs(ctx, jctx, cmdArgs)
 	case "Slack:Serve":
 		runErr = runSlackServe(ctx, jctx, cmdArgs)
+	case "Podcast:Serve":
+		runErr = runPodcastServe(ctx, jctx, cmdArgs)
 	case "Slack:CreateImageChannel":
 		runErr = runSlackCreateImageChannel(ctx, jctx, cmdArgs)
 	case "Slack:PruneImageThreads":
@@ -182,7 +220,7 @@ func requiresQueue(cmd string) bool {
 		return true
 	}
 	switch cmd {
-	case "Ai:SplitText", "content:query", "tts:SplitJobs":
+	case "Ai:GenerateFunFacts", "Gemini:GenerateFunFact", "Ai:SplitText", "app:fabric-extract-wisdom", "Collection:GenerateContent", "content:query", "tts:SplitJobs":
 		return true
 	default:
 		return false
@@ -504,6 +542,50 @@ func runCorrectSubtitles(ctx context.Context, jctx jobs.JobContext, args []strin
 	return job.Run(ctx, jctx, opts)
 }
 
+func runReviewFunFact(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("job:ReviewFunFact", flag.ContinueOnError)
+	sleep := fs.Int("sleep", 30, "Sleep time in seconds")
+	queueFlag := fs.Bool("queue", false, "Process queue messages")
+	regenerate := fs.Bool("regenerate", false, "Review again even if already reviewed or flagged")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"sleep": true})
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+	contentID, err := parseContentID(positionalArgs)
+	if err != nil {
+		return err
+	}
+	opts := jobs.JobOptions{ContentID: contentID, Sleep: *sleep, Queue: *queueFlag, Regenerate: *regenerate}
+	logJobStart("job:ReviewFunFact", opts)
+
+	job := jobs.NewReviewFunFactJob()
+	return job.Run(ctx, jctx, opts)
+}
+
+func runTranslateSubtitles(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("job:TranslateSubtitles", flag.ContinueOnError)
+	sleep := fs.Int("sleep", 30, "Sleep time in seconds")
+	queueFlag := fs.Bool("queue", false, "Process queue messages")
+	regenerate := fs.Bool("regenerate", false, "Re-translate languages that already have a track")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"sleep": true})
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+	contentID, err := parseContentID(positionalArgs)
+	if err != nil {
+		return err
+	}
+	opts := jobs.JobOptions{ContentID: contentID, Sleep: *sleep, Queue: *queueFlag, Regenerate: *regenerate}
+	logJobStart("job:TranslateSubtitles", opts)
+
+	job := jobs.NewTranslateSubtitlesJob()
+	return job.Run(ctx, jctx, opts)
+}
+
 func runUploadTikTok(ctx context.Context, jctx jobs.JobContext, args []string) error {
 	fs := flag.NewFlagSet("job:UploadPodcastToTikTok", flag.ContinueOnError)
 	sleep := fs.Int("sleep", 30, "Sleep time in seconds")
@@ -568,7 +650,7 @@ func printUsage() {
 	fmt.Println("  --verbose   Enable diagnostic logging (can appear before or after the command).")
 	fmt.Println("Commands:")
 	fmt.Println("  migrate [up] [--dir=../db/migrations] [--dry-run] [--verbose]")
-	fmt.Println("  Ai:GenerateFunFacts [content_id] [--sleep=N] [--queue] [--verbose]")
+	fmt.Println("  Ai:GenerateFunFacts [content_id] [--count=N] [--sleep=N] [--queue] [--verbose]")
 	fmt.Println("  Ai:SplitText [--verbose]")
 	fmt.Println("  Backfill:ResponseDataToSentences [content_id] [--log] [--verbose]")
 	fmt.Println("  Check:ImageIsGenerated [--verbose]")
@@ -577,14 +659,17 @@ func printUsage() {
 	fmt.Println("  Check:YoutubeUploadEligibility [content_id] [--verbose]")
 	fmt.Println("  Check:YoutubeIsUploadable [--verbose]")
 	fmt.Println("  Check:SrtIsGenerated [--verbose]")
+	fmt.Println("  Check:SubtitlesValid [--reset] [--verbose]")
 	fmt.Println("  Check:WavIsGenerated [--verbose]")
 	fmt.Println("  Content:FindDuplicateTitles [--verbose]")
-	fmt.Println("  Content:IdentifySubject --content-id=N [--verbose]")
+	fmt.Println("  Content:FindSimilar [content_id] [--text=\"...\"] [--limit=10] [--threshold=0.92] [--verbose]")
+	fmt.Println("  Content:IdentifySubject [content_id | start end] [--content-id=N] [--force] [--min-confidence=0.6] [--limit=N] [--verbose]")
 	fmt.Println("  Content:Reset <content_id> [--delete-files] [--reset-text] [--dry-run] [--yes] [--verbose]")
 	fmt.Println("  Content:Show <content_id> [--verbose]")
 	fmt.Println("  Content:SearchTitle --q=\"blob fish\" [--limit=20] [--verbose]")
 	fmt.Println("  content:query [start] [end] [--verbose]")
 	fmt.Println("  Gemini:GenerateFunFact [content_id] [--verbose]")
+	fmt.Println("  job:ReviewFunFact [content_id] [--sleep=N] [--queue] [--regenerate] [--verbose]")
 	fmt.Println("  job:GenerateWav [content_id] [--sleep=N] [--queue] [--verbose]")
 	fmt.Println("  job:GenerateSrt [content_id] [--sleep=N] [--queue] [--verbose]")
 	fmt.Println("  job:GenerateMp3 [content_id] [--sleep=N] [--queue] [--verbose]")
@@ -595,18 +680,34 @@ func printUsage() {
 	fmt.Println("  job:GeneratePodcast [content_id] [--sleep=N] [--queue] [--force] [--verbose]")
 	fmt.Println("  job:FixSubtitles [content_id] [--sleep=N] [--queue] [--verbose]")
 	fmt.Println("  job:CorrectSubtitles [content_id] [--sleep=N] [--queue] [--verbose]")
+	fmt.Println("  job:TranslateSubtitles [content_id] [--sleep=N] [--queue] [--regenerate] [--verbose]")
 	fmt.Println("  job:UploadPodcastToTikTok [content_id] [--sleep=N] [--queue] [--info] [--verbose]")
 	fmt.Println("  job:UploadPodcastToYoutube [content_id] [--sleep=N] [--queue] [--info] [--easy-upload] [--verbose]")
+	fmt.Println("  Prompt:List [--verbose]")
+	fmt.Println("  Prompt:Render <name> [--version=N] [--subject=S] [--length=L] [--language=L] [--tone=T] [--text=T|--text-file=path|--content-id=N] [--verbose]")
 	fmt.Println("  Rss:FetchHtml [--verbose]")
-	fmt.Println("  Rss:Subscribe <url> [--verbose]")
+	fmt.Println("  Rss:Subscribe <url> [--title=...] [--verbose]")
+	fmt.Println("  Rss:List [--verbose]")
+	fmt.Println("  Rss:Pause <id|url>... [--verbose]")
+	fmt.Println("  Rss:Resume <id|url>... [--verbose]")
+	fmt.Println("  Rss:Remove <id|url>... [--verbose]")
+	fmt.Println("  Rss:Import <file.opml> [--dry-run] [--verbose]")
+	fmt.Println("  Rss:Export [file.opml] [--active] [--verbose]")
 	fmt.Println("  Subject:ProcessCollections [--verbose]")
-	fmt.Println("  Youtube:UpdateMeta [--verbose]")
-	fmt.Println("  app:fabric-extract-wisdom")
+	fmt.Println("  Subject:List [--all] [--sort=weight|used|score|name|id] [--limit=50] [--verbose]")
+	fmt.Println("  Subject:Activate <id|name>... [--verbose]")
+	fmt.Println("  Subject:Deactivate <id|name>... [--verbose]")
+	fmt.Println("  Subject:Add <name> [--keywords=a,b] [--verbose]")
+	fmt.Println("  Collection:GenerateContent [collection_id] [--limit=1] [--length=\"4 to 6\"] [--verbose]")
+	fmt.Println("  Youtube:UpdateMeta [--force] [--verbose]")
+	fmt.Println("  Youtube:Auth [--verbose]")
+	fmt.Println("  app:fabric-extract-wisdom <url> | --collection-id=N [--length=\"6 to 10\"] [--dry-run] [--verbose]")
 	fmt.Println("  chat:HiennaGPT <query> [--verbose]")
 	fmt.Println("  sentences:check [id] [--verbose]")
 	fmt.Println("  tiktok:publish [--access-token=...] [--file=...] [--verbose]")
 	fmt.Println("  tts:SplitJobs <content_id> [sentence_id] [--verbose]")
 	fmt.Println("  Slack:Serve [--listen=:8085] [--public-url=https://example.com] [--verbose]")
+	fmt.Println("  Podcast:Serve [--listen=:8086] [--public-url=https://example.com] [--verbose]")
 	fmt.Println("  Slack:CreateImageChannel --name=ai-images [--private] [--verbose]")
 	fmt.Println("  Slack:PruneImageThreads [--days=7] [--hours=N] [--minutes=N] [--limit=200] [--dry-run] [--verbose]")
 }
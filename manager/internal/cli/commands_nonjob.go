@@ -4,16 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -21,164 +20,116 @@ import (
 	"unicode"
 
 	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/funfact"
 	"ai-things/manager-go/internal/jobs"
+	"ai-things/manager-go/internal/llm"
+	"ai-things/manager-go/internal/prompts"
+	"ai-things/manager-go/internal/readable"
+	"ai-things/manager-go/internal/subtitles"
 	"ai-things/manager-go/internal/utils"
+	"ai-things/manager-go/internal/wisdom"
+	"ai-things/manager-go/internal/youtube"
 )
 
+// funFactMaxWaiting pauses generation while this many fun facts are still waiting for TTS.
+const funFactMaxWaiting = 100
+
 func runAiGenerateFunFacts(ctx context.Context, jctx jobs.JobContext, args []string) error {
 	fs := flag.NewFlagSet("Ai:GenerateFunFacts", flag.ContinueOnError)
-	sleep := fs.Int("sleep", 30, "Sleep duration in seconds")
-	queueFlag := fs.Bool("queue", false, "Process queue messages")
+	sleep := fs.Int("sleep", 30, "Seconds to wait between fun facts (and while the backlog is full)")
+	queueFlag := fs.Bool("queue", false, "Keep generating whenever the TTS backlog has room (ignores --count)")
+	count := fs.Int("count", 1, "Number of fun facts to generate")
 	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
-	if err := fs.Parse(args); err != nil {
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"sleep": true, "count": true})
+	if err := fs.Parse(flagArgs); err != nil {
 		return err
 	}
 	utils.ConfigureLogging(*verbose)
-	_ = sleep
-	_ = queueFlag
 
-	contentID, err := parseContentID(fs.Args())
+	contentID, err := parseContentID(positionalArgs)
 	if err != nil {
 		return err
 	}
-	utils.Info(
-		"Ai:GenerateFunFacts start",
-		"content_id", contentID,
-		"host", jctx.Config.OllamaHostname,
-		"port", jctx.Config.OllamaPort,
-		"model", jctx.Config.OllamaModel,
-	)
-
-	text, title, paragraphs, count, err := generateOllamaFunFact(ctx, jctx.Config.OllamaHostname, jctx.Config.OllamaPort, jctx.Config.OllamaModel)
-	if err != nil {
-		return err
+	if contentID != 0 && (*count > 1 || *queueFlag) {
+		return errors.New("content_id cannot be combined with --count or --queue")
 	}
-	_ = text
-	utils.Debug("Ai:GenerateFunFacts response", "title_len", len(title), "paragraphs", len(paragraphs), "count", count)
 
-	metaJSON, err := json.Marshal(map[string]any{})
-	if err != nil {
-		return err
-	}
-	sentencesJSON, err := json.Marshal(paragraphs)
+	provider, err := jobs.NewLLM(jctx.Config, "funfact")
 	if err != nil {
 		return err
 	}
-
-	if contentID == 0 {
-		status := "new"
-		contentType := "text-to-tts"
-		_, err := jctx.Store.CreateContent(ctx, db.Content{
-			Title:     title,
-			Status:    &status,
-			Type:      &contentType,
-			Sentences: sentencesJSON,
-			Count:     count,
-			Meta:      metaJSON,
-		})
-		return err
-	}
-
-	return jctx.Store.UpdateContentText(ctx, contentID, title, sentencesJSON, count, metaJSON)
-}
-
-func generateOllamaFunFact(ctx context.Context, ollamaHostname string, ollamaPort int, ollamaModel string) (string, string, []map[string]any, int, error) {
-	prompt := strings.TrimSpace(`Write 6 to 10 paragraphs about a single unique random fact about Earth's Rotation,
-make the explanation engaging while keeping it simple.
-Your response must be in format structured exactly like this, no extra formatting required:
-TITLE: The title for the subject comes here
-CONTENT: Your entire fun fact goes here.`)
-
-	if ollamaHostname == "" {
-		return "", "", nil, 0, errors.New("missing ollama hostname (set ollama.hostname in config.ini)")
-	}
-	if ollamaPort == 0 {
-		ollamaPort = 11434
-	}
-	if strings.TrimSpace(ollamaModel) == "" {
-		ollamaModel = "llama3.2"
-	}
-	utils.Debug(
-		"ollama generate",
-		"url", fmt.Sprintf("http://%s:%d/api/generate", ollamaHostname, ollamaPort),
-		"model", ollamaModel,
-		"prompt_len", len(prompt),
+	utils.Info(
+		"Ai:GenerateFunFacts start",
+		"content_id", contentID,
+		"count", *count,
+		"queue", *queueFlag,
+		"provider", provider.Name(),
+		"model", provider.Model(),
 	)
 
-	payload := map[string]any{
-		"model":      ollamaModel,
-		"keep_alive": 300,
-		"prompt":     prompt,
-		"stream":     false,
-		"options": map[string]any{
-			"seed":        time.Now().Unix(),
-			"temperature": 1,
-		},
-	}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return "", "", nil, 0, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("http://%s:%d/api/generate", ollamaHostname, ollamaPort), bytes.NewReader(body))
-	if err != nil {
-		return "", "", nil, 0, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 600 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", "", nil, 0, err
+	// Ollama answers are more varied with a high temperature and a fresh seed per fact.
+	newRequest := func() llm.Request {
+		return llm.Request{Temperature: llm.Float(1), Seed: llm.Int64(time.Now().UnixNano())}
 	}
-	defer resp.Body.Close()
-	utils.Debug("ollama response", "status", resp.StatusCode)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", "", nil, 0, fmt.Errorf("ollama response status %d", resp.StatusCode)
+	if contentID != 0 {
+		_, err := generateSubjectFunFact(ctx, jctx, provider, contentID, "6 to 10", newRequest())
+		return err
 	}
 
-	var decoded struct {
-		Response string `json:"response"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
-		return "", "", nil, 0, err
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer stop()
+	wait := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(time.Duration(*sleep) * time.Second):
+			return true
+		}
 	}
-	utils.Debug("ollama decoded", "response_len", len(decoded.Response))
 
-	title := ""
-	paragraphs := []map[string]any{}
-	count := 0
-	prevSpacer := false
-	lines := strings.Split(decoded.Response, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "TITLE:") {
-			title = strings.TrimSpace(strings.TrimPrefix(line, "TITLE:"))
-		} else if line != "" {
-			line = strings.TrimSpace(strings.TrimPrefix(line, "CONTENT:"))
-			for _, sentence := range splitSentences(line, ".!?") {
-				if strings.TrimSpace(sentence) == "" {
-					continue
+	created := 0
+	for *queueFlag || created < *count {
+		if *queueFlag {
+			waiting, err := jctx.Store.CountContent(ctx, funFactBacklogWhere())
+			if err != nil {
+				return err
+			}
+			if waiting >= funFactMaxWaiting {
+				utils.Debug("Ai:GenerateFunFacts backlog full; sleeping", "waiting", waiting, "max_waiting", funFactMaxWaiting, "sleep_s", *sleep)
+				if !wait() {
+					return nil
 				}
-				count++
-				paragraphs = append(paragraphs, map[string]any{
-					"count":   count,
-					"content": strings.TrimSpace(sentence),
-				})
+				continue
 			}
-			prevSpacer = false
 		}
-		if !prevSpacer {
-			count++
-			paragraphs = append(paragraphs, map[string]any{
-				"count":   count,
-				"content": "<spacer>",
-			})
-			prevSpacer = true
+
+		id, err := generateSubjectFunFact(ctx, jctx, provider, 0, "6 to 10", newRequest())
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		created++
+		utils.Info("Ai:GenerateFunFacts created", "content_id", id, "created", created)
+
+		if (*queueFlag || created < *count) && !wait() {
+			return nil
 		}
 	}
+	return nil
+}
 
-	return decoded.Response, title, paragraphs, count, nil
+// funFactBacklogWhere matches fun facts that are waiting for TTS.
+func funFactBacklogWhere() string {
+	where := "WHERE type = 'gemini.payload'"
+	if cond := db.StatusTrueCondition([]string{"funfact_created"}); cond != "" {
+		where += " AND " + cond
+	}
+	if cond := db.StatusNotTrueCondition([]string{"wav_generated"}); cond != "" {
+		where += " AND " + cond
+	}
+	return where
 }
 
 func runAiSplitText(ctx context.Context, jctx jobs.JobContext, args []string) error {
@@ -634,6 +585,42 @@ func runCheckWavIsGenerated(ctx context.Context, jctx jobs.JobContext, args []st
 	})
 }
 
+func runCheckSubtitlesValid(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	return runGeneratedFilesCheck(ctx, jctx, args, "SubtitlesValid", "WHERE "+db.StatusTrueCondition([]string{"srt_generated"}), func(content db.Content, meta map[string]any) (bool, string, error) {
+		srt, _ := utils.GetString(meta, "subtitles", "srt")
+		if strings.TrimSpace(srt) == "" {
+			return true, "srt missing", nil
+		}
+		opts := subtitles.ValidateOptions{
+			Tolerance:         500 * time.Millisecond,
+			MinAlignmentScore: jctx.Config.SubtitlesMinAlignmentScore,
+		}
+		if raw, ok := utils.GetValue(meta, "mp3s", "0", "duration"); ok {
+			if seconds, ok := raw.(float64); ok {
+				opts.AudioDuration = time.Duration(seconds * float64(time.Second))
+			}
+		}
+		if text, err := utils.ExtractTextFromMeta(meta); err == nil {
+			opts.OriginalText = text
+		}
+		issues := subtitles.ValidateSRT(srt, opts)
+		if len(issues) == 0 {
+			return false, "subtitles ok", nil
+		}
+		reasons := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			reasons = append(reasons, issue.String())
+		}
+		return true, strings.Join(reasons, "; "), nil
+	}, func(meta map[string]any) {
+		delete(meta, "subtitles")
+		utils.SetStatus(meta, "srt_generated", false)
+		utils.SetStatus(meta, "srt_fixed", false)
+		utils.SetStatus(meta, "srt_flagged", false)
+		utils.SetStatus(meta, "podcast_ready", false)
+	}, false)
+}
+
 type checkResetter func(meta map[string]any)
 type checkPredicate func(content db.Content, meta map[string]any) (bool, string, error)
 
@@ -649,8 +636,18 @@ func checkGeneratedFiles(ctx context.Context, jctx jobs.JobContext, args []strin
 }
 
 func checkGeneratedFilesWhere(ctx context.Context, jctx jobs.JobContext, args []string, checkName string, where string, predicate checkPredicate, reset checkResetter) error {
+	return runGeneratedFilesCheck(ctx, jctx, args, checkName, where, predicate, reset, true)
+}
+
+// runGeneratedFilesCheck walks the rows matching where and resets the ones the predicate flags.
+// When alwaysReset is false the check only reports flagged rows unless --reset is passed.
+func runGeneratedFilesCheck(ctx context.Context, jctx jobs.JobContext, args []string, checkName string, where string, predicate checkPredicate, reset checkResetter, alwaysReset bool) error {
 	fs := flag.NewFlagSet("Check:"+checkName, flag.ContinueOnError)
 	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	doReset := &alwaysReset
+	if !alwaysReset {
+		doReset = fs.Bool("reset", false, "Reset flagged rows (default only reports them)")
+	}
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -682,6 +679,10 @@ func checkGeneratedFilesWhere(ctx context.Context, jctx jobs.JobContext, args []
 			if needsReset {
 				flagged++
 				utils.Warn("check row", "check", checkName, "content_id", content.ID, "decision", "flagged", "reason", reason)
+				if !*doReset {
+					lastID = content.ID
+					continue
+				}
 				reset(meta)
 				if err := jctx.Store.UpdateContentMeta(ctx, content.ID, meta); err != nil {
 					return err
@@ -744,6 +745,92 @@ func runContentFindDuplicateTitles(ctx context.Context, jctx jobs.JobContext, ar
 	return nil
 }
 
+// runContentFindSimilar lists content semantically close to a content or a text, or (with
+// neither) every pair of contents at or above the threshold. Missing embeddings are computed first.
+func runContentFindSimilar(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Content:FindSimilar", flag.ContinueOnError)
+	text := fs.String("text", "", "Find content similar to this text instead of a content")
+	limit := fs.Int("limit", 10, "Max matches per query")
+	threshold := fs.Float64("threshold", -1, "Minimum cosine similarity (default: 0 for a query, similarity.threshold for all pairs)")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"text": true, "limit": true, "threshold": true})
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	contentID, err := parseContentID(positionalArgs)
+	if err != nil {
+		return err
+	}
+	if contentID != 0 && strings.TrimSpace(*text) != "" {
+		return errors.New("content_id cannot be combined with --text")
+	}
+
+	embedder, err := jobs.NewEmbedder(jctx.Config)
+	if err != nil {
+		return err
+	}
+	embedded, err := jobs.EmbedMissingContents(ctx, jctx, embedder, 0)
+	if err != nil {
+		return err
+	}
+	if embedded > 0 {
+		utils.Info("content embeddings backfilled", "model", embedder.Model(), "embedded", embedded)
+	}
+	stored, err := jctx.Store.ListContentEmbeddings(ctx, embedder.Model())
+	if err != nil {
+		return err
+	}
+
+	var vector []float32
+	switch {
+	case contentID != 0:
+		content, err := jctx.Store.GetContentByID(ctx, contentID)
+		if err != nil {
+			return err
+		}
+		if vector, err = jobs.EmbedContent(ctx, jctx, embedder, content); err != nil {
+			return err
+		}
+	case strings.TrimSpace(*text) != "":
+		vectors, err := embedder.Embed(ctx, []string{strings.TrimSpace(*text)})
+		if err != nil {
+			return err
+		}
+		vector = vectors[0]
+	default:
+		minSimilarity := *threshold
+		if minSimilarity < 0 {
+			minSimilarity = jctx.Config.SimilarityThreshold
+		}
+		pairs := 0
+		for i, e := range stored {
+			// Compare with later rows only so each pair is listed once.
+			for _, match := range jobs.RankSimilar(stored[i+1:], e.Embedding, e.ContentID, minSimilarity, *limit) {
+				utils.Info("similar contents",
+					"content_id", e.ContentID,
+					"title", strings.TrimSpace(e.Title),
+					"similar_content_id", match.ContentID,
+					"similar_title", strings.TrimSpace(match.Title),
+					"similarity", fmt.Sprintf("%.3f", match.Similarity),
+				)
+				pairs++
+			}
+		}
+		utils.Info("content find similar", "model", embedder.Model(), "contents", len(stored), "threshold", minSimilarity, "pairs", pairs)
+		return nil
+	}
+
+	minSimilarity := max(*threshold, 0)
+	matches := jobs.RankSimilar(stored, vector, contentID, minSimilarity, *limit)
+	for _, match := range matches {
+		utils.Info("content match", "content_id", match.ContentID, "title", strings.TrimSpace(match.Title), "similarity", fmt.Sprintf("%.3f", match.Similarity))
+	}
+	utils.Info("content find similar", "model", embedder.Model(), "content_id", contentID, "threshold", minSimilarity, "matches", len(matches))
+	return nil
+}
+
 func pickBestDuplicate(contents []db.Content) int64 {
 	if len(contents) == 0 {
 		return 0
@@ -855,29 +942,111 @@ func metaInt(meta map[string]any, key string) int {
 	}
 }
 
+// runContentIdentifySubject classifies content into subjects: one content (content_id or
+// --content-id), an id range (start end), or every content without a subject.
 func runContentIdentifySubject(ctx context.Context, jctx jobs.JobContext, args []string) error {
 	fs := flag.NewFlagSet("Content:IdentifySubject", flag.ContinueOnError)
-	contentID := fs.Int64("content-id", 0, "The ID of the content to analyze")
+	contentIDFlag := fs.Int64("content-id", 0, "The ID of the content to analyze")
+	force := fs.Bool("force", false, "Identify again content that already has a subject")
+	minConfidence := fs.Float64("min-confidence", 0.6, "Minimum confidence (0-1) to assign the subject")
+	limit := fs.Int("limit", 0, "Max contents to process in batch mode (0 = all)")
 	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
-	if err := fs.Parse(args); err != nil {
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"content-id": true, "min-confidence": true, "limit": true})
+	if err := fs.Parse(flagArgs); err != nil {
 		return err
 	}
 	utils.ConfigureLogging(*verbose)
 
-	if *contentID == 0 {
-		return errors.New("content-id is required")
-	}
-	content, err := jctx.Store.GetContentByID(ctx, *contentID)
+	provider, err := jobs.NewLLM(jctx.Config, "subjects")
 	if err != nil {
 		return err
 	}
+	identify := func(content db.Content) error {
+		match, err := jobs.IdentifySubject(ctx, jctx, provider, content, *minConfidence)
+		if err != nil {
+			return err
+		}
+		if !match.Assigned {
+			utils.Warn("content subject low confidence; not assigned", "content_id", content.ID, "confidence", match.Confidence, "min_confidence", *minConfidence)
+			return nil
+		}
+		utils.Info("content subject identified",
+			"content_id", content.ID,
+			"title", strings.TrimSpace(content.Title),
+			"subject_id", match.Subject.ID,
+			"subject", match.Subject.Subject,
+			"confidence", match.Confidence,
+			"created", match.Created,
+		)
+		return nil
+	}
 
-	meta, err := utils.DecodeMeta(content.Meta)
+	contentID := *contentIDFlag
+	if contentID == 0 && len(positionalArgs) == 1 {
+		if contentID, err = parseContentID(positionalArgs); err != nil {
+			return err
+		}
+	}
+	if contentID != 0 {
+		content, err := jctx.Store.GetContentByID(ctx, contentID)
+		if err != nil {
+			return err
+		}
+		return identify(content)
+	}
+
+	startID, endID, err := parseOptionalRange(positionalArgs)
 	if err != nil {
 		return err
 	}
-	meta["subject"] = "pending_ai_analysis"
-	return jctx.Store.UpdateContentMeta(ctx, content.ID, meta)
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer stop()
+
+	lastID := int64(0)
+	processed, failed := 0, 0
+	for *limit == 0 || processed < *limit {
+		query := `
+			SELECT id, title, status, type, sentences, count, meta, archive, created_at, updated_at
+			FROM contents
+			WHERE id > $1
+		`
+		queryArgs := []any{lastID}
+		if !*force {
+			query += " AND meta->'subject'->>'id' IS NULL"
+		}
+		if startID != nil && endID != nil {
+			query += " AND id BETWEEN $2 AND $3"
+			queryArgs = append(queryArgs, *startID, *endID)
+		}
+		query += " ORDER BY id LIMIT 50"
+		contents, err := jctx.Store.QueryContents(ctx, query, queryArgs...)
+		if err != nil {
+			return err
+		}
+		if len(contents) == 0 {
+			break
+		}
+		for _, content := range contents {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if *limit > 0 && processed >= *limit {
+				break
+			}
+			lastID = content.ID
+			processed++
+			if err := identify(content); err != nil {
+				// One bad content (no text, unusable answer) must not stop a backfill.
+				utils.Warn("content subject identification failed", "content_id", content.ID, "err", err)
+				failed++
+			}
+		}
+	}
+	utils.Info("Content:IdentifySubject done", "processed", processed, "failed", failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d contents failed", failed, processed)
+	}
+	return nil
 }
 
 func runContentQuery(ctx context.Context, jctx jobs.JobContext, args []string) error {
@@ -1044,6 +1213,12 @@ func runContentReset(ctx context.Context, jctx jobs.JobContext, args []string) e
 			candidates = append(candidates, filepath.Join(baseOut, "images", fn))
 		}
 	}
+	// mixed narration + music bed
+	if mix, ok := utils.GetMap(meta, "music"); ok {
+		if fn, _ := mix["filename"].(string); strings.TrimSpace(fn) != "" {
+			candidates = append(candidates, filepath.Join(baseOut, "mixed", fn))
+		}
+	}
 	// podcast mp4
 	if pod, ok := utils.GetMap(meta, "podcast"); ok {
 		if fn, _ := pod["filename"].(string); strings.TrimSpace(fn) != "" {
@@ -1074,6 +1249,7 @@ func runContentReset(ctx context.Context, jctx jobs.JobContext, args []string) e
 		"mp3s",
 		"subtitles",
 		"thumbnail",
+		"music",
 		"podcast",
 		"slack_youtube_review_request",
 		"slack_youtube_review_requests",
@@ -1211,6 +1387,10 @@ func runGeminiGenerateFunFact(ctx context.Context, jctx jobs.JobContext, args []
 	if err != nil {
 		return err
 	}
+	provider, err := jobs.NewLLM(jctx.Config, "subject_funfact")
+	if err != nil {
+		return err
+	}
 
 	if contentID == 0 {
 		count, err := jctx.Store.CountContent(ctx, "WHERE "+db.StatusTrueCondition([]string{"funfact_created"}))
@@ -1227,289 +1407,330 @@ func runGeminiGenerateFunFact(ctx context.Context, jctx jobs.JobContext, args []
 			if ctx.Err() != nil {
 				return nil
 			}
-			if err := generateGeminiFunFact(ctx, jctx, 0); err != nil {
+			if _, err := generateSubjectFunFact(ctx, jctx, provider, 0, "10 to 15", llm.Request{}); err != nil {
 				return err
 			}
 			time.Sleep(2 * time.Second)
 		}
 	}
 
-	return generateGeminiFunFact(ctx, jctx, contentID)
+	_, err = generateSubjectFunFact(ctx, jctx, provider, contentID, "10 to 15", llm.Request{})
+	return err
 }
 
-func generateGeminiFunFact(ctx context.Context, jctx jobs.JobContext, contentID int64) error {
-	apiKey := jctx.Config.GeminiAPIKey
-	if apiKey == "" {
-		return errors.New("missing gemini api key (set gemini.api_key in config.ini)")
-	}
-
-	subject, err := jctx.Store.FindRandomSubject(ctx)
+// generateSubjectFunFact writes a fun fact about a subject picked by jobs.SelectSubject and stores
+// it as pipeline content (a new row when contentID is 0), then enqueues funfact_created when a
+// queue is configured.
+// length is the paragraph range for the prompt; req carries the sampling options.
+func generateSubjectFunFact(ctx context.Context, jctx jobs.JobContext, provider llm.Provider, contentID int64, length string, req llm.Request) (int64, error) {
+	subject, err := jobs.SelectSubject(ctx, jctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if subject.ID == 0 {
-		return errors.New("no available subjects found")
-	}
-
-	prompt := fmt.Sprintf(`# INSTRUCTIONS
-Write 10 to 15 paragraphs about a single unique random fact of any topic that you can think of about %s,
-make the explanation engaging while keeping it simple. You must use the specified output format.
-
-# SAMPLE OUTPUT FORMAT:
-TITLE: The title for the subject
-CONTENT: The content about the fun fact`, subject.Subject)
-
-	response, err := callGemini(ctx, apiKey, prompt)
-	if err != nil {
-		return err
-	}
-
-	text := geminiExtractText(response)
-	if text == "" {
-		return errors.New("gemini response text missing")
-	}
-
-	text = strings.ReplaceAll(text, "\n\n", "\n")
-	text = strings.ReplaceAll(text, "***", "")
-	text = strings.ReplaceAll(text, "**", "")
-
-	title := ""
-	paragraphs := []map[string]any{}
-	count := 0
-	prevSpacer := false
-	punctuationSpacers := map[rune]int{
-		'.': 3,
-		'!': 3,
-		'?': 3,
-		';': 2,
-		',': 1,
-	}
+		return 0, errors.New("no available subjects found")
+	}
+
+	dedupe := newFunFactDedupe(ctx, jctx, contentID)
+	var avoid []string
+	var prompt prompts.Prompt
+	var fact funfact.FunFact
+	var response llm.Response
+	for attempt := 0; ; attempt++ {
+		prompt, err = jobs.PromptLibrary(jctx.Config).Render("funfact", 0, prompts.Vars{
+			Subject: subject.Subject,
+			Length:  length,
+			Avoid:   avoid,
+		})
+		if err != nil {
+			return 0, err
+		}
+		fact, response, err = funfact.Generate(ctx, provider, prompt, req, jctx.Config.LLMRepairAttempts)
+		if err != nil {
+			return 0, err
+		}
 
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "TITLE:") {
-			title = strings.TrimSpace(strings.TrimPrefix(line, "TITLE:"))
-		} else if line != "" {
-			line = strings.TrimSpace(strings.TrimPrefix(line, "CONTENT:"))
-			for _, sentence := range splitSentences(line, ".!?;,") {
-				sentence = strings.TrimSpace(sentence)
-				if sentence == "" {
-					continue
-				}
-				last := rune(sentence[len(sentence)-1])
-				spacer := punctuationSpacers[last]
-				if spacer == 0 {
-					spacer = 2
-				}
-				count++
-				paragraphs = append(paragraphs, map[string]any{
-					"count":   count,
-					"content": sentence,
-				})
-				count++
-				paragraphs = append(paragraphs, map[string]any{
-					"count":   count,
-					"content": fmt.Sprintf("<spacer %d>", spacer),
-				})
-			}
-			prevSpacer = false
+		match, ok := dedupe.check(ctx, fact)
+		if !ok {
+			break
 		}
-		if !prevSpacer {
-			count++
-			paragraphs = append(paragraphs, map[string]any{
-				"count":   count,
-				"content": "<spacer 3>",
-			})
-			prevSpacer = true
+		utils.Warn("fun fact too similar to existing content",
+			"title", fact.Title,
+			"similar_content_id", match.ContentID,
+			"similar_title", match.Title,
+			"similarity", match.Similarity,
+			"attempt", attempt+1,
+		)
+		if attempt >= jctx.Config.SimilarityRegenerations {
+			return 0, fmt.Errorf("fun fact %q is too similar to content %d %q (%.3f)", fact.Title, match.ContentID, match.Title, match.Similarity)
+		}
+		avoid = append(avoid, match.Title)
+		if req.Seed != nil {
+			req.Seed = llm.Int64(*req.Seed + 1)
 		}
 	}
-
 	metaPayload := map[string]any{
-		"status": map[string]any{
-			"funfact_created":     true,
-			"wav_generated":       false,
-			"mp3_generated":       false,
-			"podcast_ready":       false,
-			"youtube_uploaded":    false,
-			"srt_generated":       false,
-			"thumbnail_generated": false,
+		"llm_response": response,
+		"subject": map[string]any{
+			"id":       subject.ID,
+			"name":     subject.Subject,
+			"strategy": jctx.Config.SubjectStrategy,
 		},
-		"sentences":       paragraphs,
-		"gemini_response": response,
 	}
+	prompt.Record(metaPayload)
+	dedupe.record(metaPayload, len(avoid))
+
+	if contentID, err = saveFunFactContent(ctx, jctx, contentID, fact, metaPayload); err != nil {
+		return 0, err
+	}
+	dedupe.store(ctx, contentID)
+	if err := jctx.Store.IncrementSubjectPodcasts(ctx, subject.ID); err != nil {
+		return contentID, err
+	}
+	return contentID, publishFunFactCreated(jctx, contentID)
+}
 
-	metaJSON, err := json.Marshal(metaPayload)
+// saveFunFactContent stores fact as pipeline content ready for TTS (a new row when contentID is
+// 0) and returns its id. meta carries the caller's extra keys; the narration keys (status,
+// sentences, funfact, original_text) are filled in here.
+func saveFunFactContent(ctx context.Context, jctx jobs.JobContext, contentID int64, fact funfact.FunFact, meta map[string]any) (int64, error) {
+	paragraphs, count := funfact.Sentences(fact.Paragraphs)
+	meta["status"] = map[string]any{
+		"funfact_created":     true,
+		"wav_generated":       false,
+		"mp3_generated":       false,
+		"podcast_ready":       false,
+		"youtube_uploaded":    false,
+		"srt_generated":       false,
+		"thumbnail_generated": false,
+	}
+	meta["sentences"] = paragraphs
+	meta["funfact"] = fact.Meta()
+	meta["original_text"] = fact.Text()
+
+	metaJSON, err := json.Marshal(meta)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	sentencesJSON, err := json.Marshal(paragraphs)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	status := "funfact_created"
 	contentType := "gemini.payload"
 	content := db.Content{
 		ID:        contentID,
-		Title:     title,
+		Title:     fact.Title,
 		Status:    &status,
 		Type:      &contentType,
 		Sentences: sentencesJSON,
 		Count:     count,
 		Meta:      metaJSON,
 	}
-
 	if contentID == 0 {
-		if _, err := jctx.Store.CreateContent(ctx, content); err != nil {
-			return err
-		}
-	} else {
-		if err := jctx.Store.UpsertContentByID(ctx, content); err != nil {
-			return err
-		}
+		return jctx.Store.CreateContent(ctx, content)
 	}
+	return contentID, jctx.Store.UpsertContentByID(ctx, content)
+}
 
-	return jctx.Store.IncrementSubjectPodcasts(ctx, subject.ID)
+// publishFunFactCreated hands new content to job:ReviewFunFact. Every command that creates fun
+// facts connects to the queue (requiresQueue).
+func publishFunFactCreated(jctx jobs.JobContext, contentID int64) error {
+	payload, _ := json.Marshal(jobs.QueuePayload{ContentID: contentID, Hostname: ""})
+	return jctx.Queue.Publish("funfact_created", payload)
 }
 
-func runRssSubscribe(ctx context.Context, jctx jobs.JobContext, args []string) error {
-	fs := flag.NewFlagSet("Rss:Subscribe", flag.ContinueOnError)
-	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
-	if err := fs.Parse(args); err != nil {
-		return err
+// funFactDedupe compares new fun facts with the embeddings of existing content. It is inert
+// when similarity.check is off or the embedding provider is unavailable: generation must not
+// stop because the embedding server is down.
+type funFactDedupe struct {
+	jctx      jobs.JobContext
+	embedder  llm.Embedder
+	stored    []db.ContentEmbedding
+	excludeID int64
+
+	// text and vector belong to the last checked fact.
+	text    string
+	vector  []float32
+	nearest jobs.SimilarContent
+}
+
+func newFunFactDedupe(ctx context.Context, jctx jobs.JobContext, contentID int64) *funFactDedupe {
+	d := &funFactDedupe{jctx: jctx, excludeID: contentID}
+	if !jctx.Config.SimilarityCheck {
+		return d
 	}
-	utils.ConfigureLogging(*verbose)
-
-	if len(fs.Args()) == 0 {
-		return errors.New("url is required")
-	}
-	rawURL := fs.Args()[0]
-	parsed, err := url.ParseRequestURI(rawURL)
-	if err != nil || parsed.Scheme == "" {
-		return fmt.Errorf("invalid url: %s", rawURL)
+	embedder, err := jobs.NewEmbedder(jctx.Config)
+	if err != nil {
+		utils.Warn("similarity check disabled", "err", err)
+		return d
 	}
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Get(rawURL)
+	stored, err := jctx.Store.ListContentEmbeddings(ctx, embedder.Model())
 	if err != nil {
-		return err
+		utils.Warn("similarity check disabled: list embeddings failed", "err", err)
+		return d
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("rss fetch failed status=%d", resp.StatusCode)
+	d.embedder = embedder
+	d.stored = stored
+	return d
+}
+
+// check embeds fact and returns the closest existing content when it is at or above the
+// similarity threshold.
+func (d *funFactDedupe) check(ctx context.Context, fact funfact.FunFact) (jobs.SimilarContent, bool) {
+	d.text, d.vector, d.nearest = "", nil, jobs.SimilarContent{}
+	if d.embedder == nil {
+		return jobs.SimilarContent{}, false
 	}
-	body, err := io.ReadAll(resp.Body)
+	text := jobs.EmbeddingText(fact.Title, fact.Text())
+	vectors, err := d.embedder.Embed(ctx, []string{text})
 	if err != nil {
-		return err
+		utils.Warn("similarity check skipped: embed failed", "err", err)
+		return jobs.SimilarContent{}, false
+	}
+	d.text, d.vector = text, vectors[0]
+	if nearest := jobs.RankSimilar(d.stored, d.vector, d.excludeID, 0, 1); len(nearest) > 0 {
+		d.nearest = nearest[0]
 	}
+	return d.nearest, d.nearest.ContentID != 0 && d.nearest.Similarity >= d.jctx.Config.SimilarityThreshold
+}
 
-	channel, err := parseRSSChannel(body)
-	if err != nil {
-		return err
+// record stores the outcome of the check in meta.similarity.
+func (d *funFactDedupe) record(meta map[string]any, regenerations int) {
+	if d.vector == nil {
+		return
+	}
+	meta["similarity"] = map[string]any{
+		"model":              d.embedder.Model(),
+		"threshold":          d.jctx.Config.SimilarityThreshold,
+		"nearest_content_id": d.nearest.ContentID,
+		"nearest_similarity": d.nearest.Similarity,
+		"regenerations":      regenerations,
 	}
+}
 
-	now := time.Now()
-	sub := db.Subscription{
-		FeedURL:       rawURL,
-		Title:         channel.Title,
-		Description:   channel.Description,
-		SiteURL:       channel.Link,
-		LastFetchedAt: &now,
-		IsActive:      true,
+// store saves the embedding of the accepted fact so later facts are compared against it.
+func (d *funFactDedupe) store(ctx context.Context, contentID int64) {
+	if d.vector == nil {
+		return
 	}
-	return jctx.Store.InsertSubscription(ctx, sub)
+	if err := jobs.StoreEmbedding(ctx, d.jctx, d.embedder, contentID, d.text, d.vector); err != nil {
+		utils.Warn("store content embedding failed", "content_id", contentID, "err", err)
+	}
+	// Later checks in the same run (batches) compare against this fact too.
+	d.stored = append(d.stored, db.ContentEmbedding{ContentID: contentID, Model: d.embedder.Model(), Embedding: d.vector})
 }
 
-func runRssFetchHtml(ctx context.Context, jctx jobs.JobContext, args []string) error {
-	fs := flag.NewFlagSet("Rss:FetchHtml", flag.ContinueOnError)
+func runSubjectProcessCollections(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Subject:ProcessCollections", flag.ContinueOnError)
 	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 	utils.ConfigureLogging(*verbose)
 
-	subscriptions, err := jctx.Store.ListActiveSubscriptions(ctx)
+	provider, err := jobs.NewLLM(jctx.Config, "subjects")
 	if err != nil {
 		return err
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	for _, sub := range subscriptions {
-		resp, err := client.Get(sub.FeedURL)
-		if err != nil {
-			return err
-		}
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+	lastID := int64(0)
+	for {
+		collections, err := jctx.Store.ListCollectionsUnprocessed(ctx, lastID, 100)
 		if err != nil {
 			return err
 		}
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return fmt.Errorf("rss fetch failed status=%d url=%s", resp.StatusCode, sub.FeedURL)
-		}
-
-		items, err := parseTrendingItems(body)
-		if err != nil {
-			return err
+		if len(collections) == 0 {
+			break
 		}
-		for _, item := range items {
-			for _, news := range item.NewsItems {
-				if news.URL == "" {
-					continue
-				}
-				existing, err := jctx.Store.GetCollectionByURL(ctx, news.URL)
-				if err != nil {
-					return err
-				}
-				if existing.ID != 0 && existing.HTMLContent != "" {
+		for _, collection := range collections {
+			subjects, err := extractSubjects(ctx, provider, jobs.PromptLibrary(jctx.Config), collectionText(collection))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "collection %d error: %v\n", collection.ID, err)
+				time.Sleep(30 * time.Second)
+				continue
+			}
+			for _, subject := range subjects {
+				subject = strings.TrimSpace(strings.ToLower(subject))
+				if subject == "" {
 					continue
 				}
-				html, err := fetchHTML(news.URL)
+				existing, err := jctx.Store.GetSubjectByName(ctx, subject)
 				if err != nil {
 					return err
 				}
-				if html == "" {
-					continue
-				}
-				if existing.ID != 0 {
-					if err := jctx.Store.UpdateCollectionHTML(ctx, existing.ID, html); err != nil {
+				if existing.ID == 0 {
+					if err := jctx.Store.InsertSubject(ctx, subject); err != nil {
 						return err
 					}
 					continue
 				}
-				coll := db.Collection{
-					URL:         news.URL,
-					Title:       news.Title,
-					Language:    "en",
-					HTMLContent: html,
-					FetchedAt:   time.Now(),
-				}
-				if err := jctx.Store.InsertCollection(ctx, coll); err != nil {
+				if err := jctx.Store.MarkSubjectSeen(ctx, existing.ID); err != nil {
 					return err
 				}
 			}
+			if err := jctx.Store.MarkCollectionProcessed(ctx, collection.ID); err != nil {
+				return err
+			}
+			lastID = collection.ID
 		}
 	}
 	return nil
 }
 
-func runSubjectProcessCollections(ctx context.Context, jctx jobs.JobContext, args []string) error {
-	fs := flag.NewFlagSet("Subject:ProcessCollections", flag.ContinueOnError)
+// runCollectionGenerateContent turns fetched articles into episodes: the readable text is
+// summarized into an attributed script (article_script prompt) stored as pipeline content with
+// meta.source.collection_id, and the collection is linked to it (collections.content_id). Batch
+// mode picks collections without content, independently of Subject:ProcessCollections.
+func runCollectionGenerateContent(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Collection:GenerateContent", flag.ContinueOnError)
+	limit := fs.Int("limit", 1, "Max contents to create in batch mode")
+	length := fs.String("length", "4 to 6", "Script length in paragraphs")
 	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
-	if err := fs.Parse(args); err != nil {
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"limit": true, "length": true})
+	if err := fs.Parse(flagArgs); err != nil {
 		return err
 	}
 	utils.ConfigureLogging(*verbose)
 
-	apiKey := jctx.Config.GeminiAPIKey
-	if apiKey == "" {
-		return errors.New("missing gemini api key (set gemini.api_key in config.ini)")
+	collectionID := int64(0)
+	if len(positionalArgs) > 0 {
+		id, err := strconv.ParseInt(positionalArgs[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid collection_id: %s", positionalArgs[0])
+		}
+		collectionID = id
+	}
+
+	provider, err := jobs.NewLLM(jctx.Config, "article")
+	if err != nil {
+		return err
+	}
+	dedupe := newFunFactDedupe(ctx, jctx, 0)
+
+	if collectionID != 0 {
+		collection, err := jctx.Store.GetCollectionByID(ctx, collectionID)
+		if err != nil {
+			return err
+		}
+		if collection.ID == 0 {
+			return fmt.Errorf("collection %d not found", collectionID)
+		}
+		if collection.ContentID != nil {
+			return fmt.Errorf("collection %d already has content %d", collection.ID, *collection.ContentID)
+		}
+		_, err = generateCollectionContent(ctx, jctx, provider, dedupe, collection, *length)
+		return err
 	}
 
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer stop()
+
+	created := 0
 	lastID := int64(0)
-	for {
-		collections, err := jctx.Store.ListCollectionsUnprocessed(ctx, lastID, 100)
+	for created < *limit {
+		collections, err := jctx.Store.ListCollectionsWithoutContent(ctx, lastID, 20)
 		if err != nil {
 			return err
 		}
@@ -1517,194 +1738,401 @@ func runSubjectProcessCollections(ctx context.Context, jctx jobs.JobContext, arg
 			break
 		}
 		for _, collection := range collections {
-			subjects, err := extractSubjects(ctx, apiKey, collection.HTMLContent)
+			if ctx.Err() != nil || created >= *limit {
+				break
+			}
+			lastID = collection.ID
+			contentID, err := generateCollectionContent(ctx, jctx, provider, dedupe, collection, *length)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "collection %d error: %v\n", collection.ID, err)
-				time.Sleep(30 * time.Second)
+				utils.Warn("collection content failed", "collection_id", collection.ID, "url", collection.URL, "err", err)
 				continue
 			}
-			for _, subject := range subjects {
-				subject = strings.TrimSpace(strings.ToLower(subject))
-				if subject == "" {
-					continue
-				}
-				existing, err := jctx.Store.GetSubjectByName(ctx, subject)
-				if err != nil {
-					return err
-				}
-				if existing.ID == 0 {
-					if err := jctx.Store.InsertSubject(ctx, subject); err != nil {
-						return err
-					}
-				}
-			}
-			if err := jctx.Store.MarkCollectionProcessed(ctx, collection.ID); err != nil {
-				return err
+			if contentID != 0 {
+				created++
 			}
-			lastID = collection.ID
+		}
+		if ctx.Err() != nil {
+			break
 		}
 	}
+	utils.Info("Collection:GenerateContent done", "created", created)
 	return nil
 }
 
+// generateCollectionContent creates the content for one collection. It returns 0 (and marks the
+// collection skipped) when the article has no readable text or repeats existing content.
+func generateCollectionContent(ctx context.Context, jctx jobs.JobContext, provider llm.Provider, dedupe *funFactDedupe, collection db.Collection, length string) (int64, error) {
+	if collection.HTMLContent == "" {
+		// Failed fetches stay pending so a later Rss:FetchHtml can fill them in.
+		return 0, fmt.Errorf("collection %d has not been fetched (status=%s: %s)", collection.ID, collection.FetchStatus, collection.FetchError)
+	}
+	text := collectionText(collection)
+	if text == "" {
+		utils.Warn("collection has no readable text; skipping", "collection_id", collection.ID, "url", collection.URL)
+		return 0, jctx.Store.MarkCollectionContentSkipped(ctx, collection.ID)
+	}
+	publication := collection.URL
+	if parsed, err := url.Parse(collection.URL); err == nil && parsed.Host != "" {
+		publication = strings.TrimPrefix(parsed.Hostname(), "www.")
+	}
+	document := fmt.Sprintf("SOURCE: %s\nURL: %s\n", publication, collection.URL)
+	if collection.Byline != "" {
+		document += fmt.Sprintf("BYLINE: %s\n", collection.Byline)
+	}
+	if collection.PublishedAt != nil {
+		document += fmt.Sprintf("PUBLISHED: %s\n", collection.PublishedAt.Format("2006-01-02"))
+	}
+	document += "\n" + text
+
+	prompt, err := jobs.PromptLibrary(jctx.Config).Render("article_script", 0, prompts.Vars{
+		Subject: collection.Title,
+		Text:    document,
+		Length:  length,
+	})
+	if err != nil {
+		return 0, err
+	}
+	fact, response, err := funfact.Generate(ctx, provider, prompt, llm.Request{}, jctx.Config.LLMRepairAttempts)
+	if err != nil {
+		return 0, err
+	}
+	if !slices.Contains(fact.Sources, collection.URL) {
+		fact.Sources = append([]string{collection.URL}, fact.Sources...)
+	}
+
+	if match, similar := dedupe.check(ctx, fact); similar {
+		utils.Warn("collection content too similar to existing content; skipping",
+			"collection_id", collection.ID,
+			"title", fact.Title,
+			"similar_content_id", match.ContentID,
+			"similarity", match.Similarity,
+		)
+		return 0, jctx.Store.MarkCollectionContentSkipped(ctx, collection.ID)
+	}
+
+	meta := map[string]any{
+		"source": map[string]any{
+			"type":          "collection",
+			"collection_id": collection.ID,
+			"url":           collection.URL,
+			"title":         collection.Title,
+			"publication":   publication,
+		},
+		"llm_response": response,
+	}
+	prompt.Record(meta)
+	dedupe.record(meta, 0)
+	contentID, err := saveFunFactContent(ctx, jctx, 0, fact, meta)
+	if err != nil {
+		return 0, err
+	}
+	dedupe.store(ctx, contentID)
+	if err := jctx.Store.LinkCollectionContent(ctx, collection.ID, contentID); err != nil {
+		return contentID, err
+	}
+	utils.Info("collection content created", "collection_id", collection.ID, "content_id", contentID, "title", fact.Title)
+	return contentID, publishFunFactCreated(jctx, contentID)
+}
+
+// runYoutubeUpdateMeta stores the view, like and comment counts of uploaded videos in contents
+// meta (view_count, like_count, comments, youtube.*) and rolls them up into subjects.score.
 func runYoutubeUpdateMeta(ctx context.Context, jctx jobs.JobContext, args []string) error {
 	fs := flag.NewFlagSet("Youtube:UpdateMeta", flag.ContinueOnError)
+	force := fs.Bool("force", false, "Refresh statistics younger than youtube.stats_max_age_hours too")
 	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 	utils.ConfigureLogging(*verbose)
 
+	client := youtube.NewClient(jctx.Config.YoutubeAPIKey)
+	maxAge := time.Duration(jctx.Config.YoutubeStatsMaxAgeHours * float64(time.Hour))
+	updated, missing := 0, 0
 	lastID := int64(0)
+batches:
 	for {
-		contents, err := listContentBatch(ctx, jctx.Store, "WHERE meta->>'video_id.v1' IS NOT NULL", nil, lastID, 100)
+		contents, err := listContentBatch(ctx, jctx.Store, "WHERE meta->>'video_id.v1' IS NOT NULL", nil, lastID, 50)
 		if err != nil {
 			return err
 		}
 		if len(contents) == 0 {
 			break
 		}
+		lastID = contents[len(contents)-1].ID
+
+		metas := map[int64]map[string]any{}
+		videoIDs := map[int64]string{}
+		var ids []string
 		for _, content := range contents {
-			if err := updateYoutubeMeta(ctx, jctx, content); err != nil {
+			meta, err := utils.DecodeMeta(content.Meta)
+			if err != nil {
+				return err
+			}
+			videoID, _ := meta["video_id.v1"].(string)
+			if videoID == "" || (!*force && youtubeStatsFresh(meta, maxAge)) {
+				continue
+			}
+			metas[content.ID] = meta
+			videoIDs[content.ID] = videoID
+			ids = append(ids, videoID)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		stats, err := client.VideoStatistics(ctx, ids)
+		if youtube.QuotaExceeded(err) {
+			// Keep what was stored so far; the rest is refreshed on the next run.
+			utils.Warn("youtube quota exceeded; stopping early", "updated", updated, "err", err)
+			break batches
+		}
+		if err != nil {
+			return err
+		}
+		now := time.Now().Format(time.RFC3339)
+		for contentID, meta := range metas {
+			youtubeMeta, _ := meta["youtube"].(map[string]any)
+			if youtubeMeta == nil {
+				youtubeMeta = map[string]any{}
+			}
+			youtubeMeta["meta_last_updated_at"] = now
+			if stat, ok := stats[videoIDs[contentID]]; ok {
+				meta["view_count"] = stat.ViewCount
+				meta["like_count"] = stat.LikeCount
+				meta["comments"] = stat.CommentCount
+				if stat.PublishedAt != nil {
+					youtubeMeta["published_at"] = stat.PublishedAt.Format(time.RFC3339)
+				}
+				delete(youtubeMeta, "missing")
+				updated++
+			} else {
+				// Deleted or private; the last known counts are kept.
+				youtubeMeta["missing"] = true
+				missing++
+				utils.Warn("youtube video not found", "content_id", contentID, "video_id", videoIDs[contentID])
+			}
+			meta["youtube"] = youtubeMeta
+			if err := jctx.Store.UpdateContentMeta(ctx, contentID, meta); err != nil {
 				return err
 			}
-			lastID = content.ID
 		}
 	}
-	return nil
-}
 
-func updateYoutubeMeta(ctx context.Context, jctx jobs.JobContext, content db.Content) error {
-	meta, err := utils.DecodeMeta(content.Meta)
+	scored, err := jobs.RollupSubjectScores(ctx, jctx)
 	if err != nil {
 		return err
 	}
+	utils.Info("youtube update meta done", "updated", updated, "missing", missing, "subjects_scored", scored)
+	return nil
+}
 
-	if youtubeMeta, ok := meta["youtube"].(map[string]any); ok {
-		if raw, ok := youtubeMeta["meta_last_updated_at"].(string); ok && raw != "" {
-			if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
-				if time.Since(parsed) < 24*time.Hour {
-					return nil
-				}
-			}
-		}
-	}
+// youtubeAuthRedirectURI is the loopback redirect of a Desktop OAuth client. Nothing listens
+// there: the browser shows an error page and the user pastes its URL back.
+const youtubeAuthRedirectURI = "http://localhost"
 
-	videoID, _ := meta["video_id.v1"].(string)
-	if videoID == "" {
-		return errors.New("video id missing in meta")
+// runYoutubeAuth authorizes the [youtube] OAuth client for uploads and writes the refresh token
+// to youtube.token_file.
+func runYoutubeAuth(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Youtube:Auth", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
+	utils.ConfigureLogging(*verbose)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.youtube.com/watch?v="+videoID, nil)
+	if jctx.Config.YoutubeClientSecretsFile == "" || jctx.Config.YoutubeTokenFile == "" {
+		return errors.New("youtube.client_secrets_file and youtube.token_file are required")
+	}
+	oauth, err := youtube.LoadClientSecrets(jctx.Config.YoutubeClientSecretsFile)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	fmt.Printf("Open this URL, allow access, then copy the URL of the page it redirects to:\n\n%s\n\n", oauth.AuthCodeURL(youtubeAuthRedirectURI))
+	input, err := utils.Prompt("Redirected URL or code")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("youtube returned status %d", resp.StatusCode)
+	code := input
+	if parsed, err := url.Parse(input); err == nil && parsed.Query().Get("code") != "" {
+		code = parsed.Query().Get("code")
 	}
-	body, err := io.ReadAll(resp.Body)
+	if code == "" {
+		return errors.New("authorization code is required")
+	}
+
+	token, err := oauth.Exchange(ctx, &http.Client{Timeout: 30 * time.Second}, code, youtubeAuthRedirectURI)
 	if err != nil {
 		return err
 	}
-	if len(body) == 0 {
-		return errors.New("empty youtube response")
+	if err := youtube.SaveToken(jctx.Config.YoutubeTokenFile, token); err != nil {
+		return err
 	}
+	fmt.Printf("saved %s (scope: %s)\n", jctx.Config.YoutubeTokenFile, token.Scope)
+	return nil
+}
 
-	if meta["youtube"] == nil {
-		meta["youtube"] = map[string]any{}
+// youtubeStatsFresh reports statistics fetched less than maxAge ago.
+func youtubeStatsFresh(meta map[string]any, maxAge time.Duration) bool {
+	raw, ok := utils.GetValue(meta, "youtube", "meta_last_updated_at")
+	if !ok {
+		return false
 	}
-	youtubeMeta, _ := meta["youtube"].(map[string]any)
-	youtubeMeta["meta_last_updated_at"] = time.Now().Format(time.RFC3339)
-	meta["youtube"] = youtubeMeta
-
-	return jctx.Store.UpdateContentMeta(ctx, content.ID, meta)
+	value, _ := raw.(string)
+	parsed, err := time.Parse(time.RFC3339, value)
+	return err == nil && time.Since(parsed) < maxAge
 }
 
+// wisdomMaxChars caps the source text sent to the model.
+const wisdomMaxChars = 30000
+
+// runAppFabricExtractWisdom turns an article (URL) or a collections row into content: it extracts
+// ideas, quotes, facts and recommendations (fabric's extract_wisdom pattern) plus a narration
+// script, stored like a generated fun fact so it goes through review and TTS.
 func runAppFabricExtractWisdom(ctx context.Context, jctx jobs.JobContext, args []string) error {
 	fs := flag.NewFlagSet("app:fabric-extract-wisdom", flag.ContinueOnError)
-	if err := fs.Parse(args); err != nil {
-		return err
-	}
-	fmt.Println("fabric-extract-wisdom: not implemented")
-	return nil
-}
-
-func runChatHiennaGPT(ctx context.Context, jctx jobs.JobContext, args []string) error {
-	fs := flag.NewFlagSet("chat:HiennaGPT", flag.ContinueOnError)
+	collectionID := fs.Int64("collection-id", 0, "Extract from this collections row instead of a URL")
+	length := fs.String("length", "6 to 10", "Script length in paragraphs")
+	dryRun := fs.Bool("dry-run", false, "Print the extraction without creating content")
 	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
-	if err := fs.Parse(args); err != nil {
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"collection-id": true, "length": true})
+	if err := fs.Parse(flagArgs); err != nil {
 		return err
 	}
 	utils.ConfigureLogging(*verbose)
 
-	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
-	if query == "" {
-		return errors.New("query is required")
+	source := map[string]any{}
+	var text, sourceURL, sourceTitle string
+	switch {
+	case *collectionID != 0:
+		collection, err := jctx.Store.GetCollectionByID(ctx, *collectionID)
+		if err != nil {
+			return err
+		}
+		if collection.ID == 0 {
+			return fmt.Errorf("collection %d not found", *collectionID)
+		}
+		text, sourceURL, sourceTitle = collectionText(collection), collection.URL, collection.Title
+		source["type"] = "collection"
+		source["collection_id"] = collection.ID
+	case len(positionalArgs) == 1:
+		parsed, err := url.ParseRequestURI(positionalArgs[0])
+		if err != nil || parsed.Scheme == "" {
+			return fmt.Errorf("invalid url: %s", positionalArgs[0])
+		}
+		sourceURL = parsed.String()
+		page, err := jobs.NewFetcher(jctx.Config).Fetch(ctx, sourceURL)
+		if err != nil {
+			return fmt.Errorf("fetch %s: %w", sourceURL, err)
+		}
+		article := readable.Extract(page.Body)
+		text, sourceTitle = readable.Truncate(article.Text, wisdomMaxChars), article.Title
+		source["type"] = "url"
+	default:
+		return errors.New("url or --collection-id is required")
 	}
+	source["url"] = sourceURL
+	source["title"] = sourceTitle
 
-	apiKey := jctx.Config.Portnumber53APIKey
-	if apiKey == "" {
-		return errors.New("missing portnumber53 api key (set portnumber53.api_key in config.ini)")
+	if text == "" {
+		return fmt.Errorf("no readable text in %s", sourceURL)
 	}
 
-	payload := map[string]any{
-		"model":  "llama3.1:8b",
-		"stream": false,
-		"prompt": fmt.Sprintf(`SYSTEM """
-You are a politician, that answers questions always trying to avoid giving a real, or even correct answer. You also add a lot of generic definitions and circular logic to your speech. Keep your answers around 100 words. Some examples:
--When people ask you about fixing the education system, you may praise the color of the buses and be excited about their color.
--When asked about how you're going to fix the economy, you bring into the conversation social rights that have nothing to do with economics.
--When asked about inflation, you will say prices have gone up, and prices being up, makes things cost more, because inflation is high.
-"""
-USER """
-%s
-"""`, query),
+	provider, err := jobs.NewLLM(jctx.Config, "wisdom")
+	if err != nil {
+		return err
 	}
-	body, err := json.Marshal(payload)
+	prompt, err := jobs.PromptLibrary(jctx.Config).Render("extract_wisdom", 0, prompts.Vars{
+		Subject: sourceTitle,
+		Text:    text,
+		Length:  *length,
+	})
 	if err != nil {
 		return err
 	}
+	utils.Info("app:fabric-extract-wisdom start", "url", sourceURL, "text_len", len(text), "provider", provider.Name(), "model", provider.Model())
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://ollama.portnumber53.com/api/generate", bytes.NewReader(body))
+	var extracted wisdom.Wisdom
+	response, err := llm.GenerateJSON(ctx, provider, llm.Request{System: prompt.System, User: prompt.User}, jctx.Config.LLMRepairAttempts, func(answer string) error {
+		parsed, err := wisdom.Parse(answer)
+		if err != nil {
+			return err
+		}
+		if err := parsed.Validate(); err != nil {
+			return err
+		}
+		extracted = parsed
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-key", apiKey)
+	script := extracted.Script
+	if sourceURL != "" && !slices.Contains(script.Sources, sourceURL) {
+		script.Sources = append([]string{sourceURL}, script.Sources...)
+	}
+
+	if *dryRun {
+		fmt.Printf("# %s\n\n%s\n", script.Title, extracted.Summary)
+		for _, section := range []struct {
+			name  string
+			items []string
+		}{
+			{"IDEAS", extracted.Ideas},
+			{"QUOTES", extracted.Quotes},
+			{"FACTS", extracted.Facts},
+			{"RECOMMENDATIONS", extracted.Recommendations},
+			{"SCRIPT", script.Paragraphs},
+		} {
+			fmt.Printf("\n## %s\n", section.name)
+			for _, item := range section.items {
+				fmt.Printf("- %s\n", item)
+			}
+		}
+		return nil
+	}
 
-	timeout := jctx.Config.Portnumber53TimeoutSeconds
-	if timeout <= 0 {
-		timeout = 1000
+	meta := map[string]any{
+		"wisdom":       extracted.Meta(),
+		"source":       source,
+		"llm_response": response,
 	}
-	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
-	resp, err := client.Do(req)
+	prompt.Record(meta)
+	contentID, err := saveFunFactContent(ctx, jctx, 0, script, meta)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("chat response status %d", resp.StatusCode)
+	utils.Info("app:fabric-extract-wisdom created", "content_id", contentID, "title", script.Title, "url", sourceURL)
+	return publishFunFactCreated(jctx, contentID)
+}
+
+func runChatHiennaGPT(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("chat:HiennaGPT", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
+	utils.ConfigureLogging(*verbose)
 
-	var decoded struct {
-		Response string `json:"response"`
+	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if query == "" {
+		return errors.New("query is required")
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+
+	provider, err := jobs.NewLLM(jctx.Config, "chat")
+	if err != nil {
 		return err
 	}
-	fmt.Println(decoded.Response)
+	response, err := provider.Generate(ctx, llm.Request{
+		System: `You are a politician, that answers questions always trying to avoid giving a real, or even correct answer. You also add a lot of generic definitions and circular logic to your speech. Keep your answers around 100 words. Some examples:
+-When people ask you about fixing the education system, you may praise the color of the buses and be excited about their color.
+-When asked about how you're going to fix the economy, you bring into the conversation social rights that have nothing to do with economics.
+-When asked about inflation, you will say prices have gone up, and prices being up, makes things cost more, because inflation is high.`,
+		User: query,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(response.Text)
 	return nil
 }
 
@@ -1992,133 +2420,26 @@ func ttsFilename(contentID int64, text string, index int) string {
 	return fmt.Sprintf("%010d-%03d-%s-%s.wav", contentID, index, "jenny", utils.MD5String(text))
 }
 
-type rssChannel struct {
-	Title       *string `xml:"channel>title"`
-	Description *string `xml:"channel>description"`
-	Link        *string `xml:"channel>link"`
-}
-
-func parseRSSChannel(data []byte) (rssChannel, error) {
-	var channel rssChannel
-	if err := xml.Unmarshal(data, &channel); err != nil {
-		return rssChannel{}, err
-	}
-	return channel, nil
-}
-
-type rssFeed struct {
-	Channel *rssChannelItems `xml:"channel"`
-	Items   []rssItem        `xml:"item"`
-}
-
-type rssChannelItems struct {
-	Items []rssItem `xml:"item"`
-}
-
-type rssItem struct {
-	Title     string    `xml:"title"`
-	NewsItems []rssNews `xml:"http://trends.google.com/trending/rss news_item"`
-}
-
-type rssNews struct {
-	Title string `xml:"http://trends.google.com/trending/rss news_item_title"`
-	URL   string `xml:"http://trends.google.com/trending/rss news_item_url"`
-}
-
-func parseTrendingItems(data []byte) ([]rssItem, error) {
-	var feed rssFeed
-	if err := xml.Unmarshal(data, &feed); err != nil {
-		return nil, err
-	}
-	if feed.Channel != nil {
-		return feed.Channel.Items, nil
-	}
-	return feed.Items, nil
-}
-
-func fetchHTML(rawURL string) (string, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("fetch %s status=%d", rawURL, resp.StatusCode)
-	}
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+// collectionText is the readable article text of a collection. Rows fetched before text_content
+// existed are extracted on the fly.
+func collectionText(collection db.Collection) string {
+	text := collection.TextContent
+	if text == "" {
+		text = readable.Extract(collection.HTMLContent).Text
 	}
-	return string(data), nil
+	return readable.Truncate(text, wisdomMaxChars)
 }
 
-func callGemini(ctx context.Context, apiKey, prompt string) (map[string]any, error) {
-	payload := map[string]any{
-		"contents": []any{
-			map[string]any{
-				"parts": []any{
-					map[string]any{
-						"text": prompt,
-					},
-				},
-			},
-		},
-	}
-	body, err := json.Marshal(payload)
+func extractSubjects(ctx context.Context, provider llm.Provider, library prompts.Library, text string) ([]string, error) {
+	prompt, err := library.Render("subjects", 0, prompts.Vars{Text: text})
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash:generateContent?key=%s", apiKey), bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 600 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("gemini response status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(body)))
-	}
-	var decoded map[string]any
-	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
-		return nil, err
-	}
-	return decoded, nil
-}
-
-func geminiExtractText(response map[string]any) string {
-	if response == nil {
-		return ""
-	}
-	if text, ok := utils.GetString(response, "candidates", "0", "content", "parts", "0", "text"); ok {
-		return text
-	}
-	return ""
-}
-
-func extractSubjects(ctx context.Context, apiKey, htmlContent string) ([]string, error) {
-	prompt := "Given the following HTML content, create a list of subjects, things, events, etc. that are mentioned or implied. Format the response as a simple comma-separated list of subjects in lowercase:\n\n" + htmlContent
-	response, err := callGemini(ctx, apiKey, prompt)
+	response, err := provider.Generate(ctx, llm.Request{System: prompt.System, User: prompt.User})
 	if err != nil {
 		return nil, err
 	}
-	text := geminiExtractText(response)
-	if text == "" {
-		return nil, errors.New("empty gemini subject response")
-	}
-	parts := strings.Split(text, ",")
+	parts := strings.Split(response.Text, ",")
 	for i, part := range parts {
 		parts[i] = strings.TrimSpace(part)
 	}
@@ -2241,6 +2562,19 @@ func listContentBatch(ctx context.Context, store *db.Store, where string, args [
 }
 
 func extractOriginalText(meta map[string]any) string {
+	// JSON fun facts keep their narration in meta.funfact.paragraphs.
+	if funfactMeta, ok := utils.GetMap(meta, "funfact"); ok {
+		if paragraphs, ok := funfactMeta["paragraphs"].([]any); ok && len(paragraphs) > 0 {
+			lines := make([]string, 0, len(paragraphs))
+			for _, paragraph := range paragraphs {
+				if text, ok := paragraph.(string); ok && strings.TrimSpace(text) != "" {
+					lines = append(lines, strings.TrimSpace(text))
+				}
+			}
+			return strings.Join(lines, "\n\n")
+		}
+	}
+
 	var payload string
 	if text, ok := utils.GetString(meta, "ollama_response", "response"); ok && text != "" {
 		payload = text
@@ -2248,6 +2582,9 @@ func extractOriginalText(meta map[string]any) string {
 	if text, ok := utils.GetString(meta, "gemini_response", "candidates", "0", "content", "parts", "0", "text"); ok && text != "" {
 		payload = text
 	}
+	if text, ok := utils.GetString(meta, "llm_response", "text"); ok && text != "" {
+		payload = text
+	}
 	if payload == "" {
 		return ""
 	}
@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/jobs"
+	"ai-things/manager-go/internal/podcastfeed"
+	"ai-things/manager-go/internal/utils"
+)
+
+func runPodcastServe(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Podcast:Serve", flag.ContinueOnError)
+	listen := fs.String("listen", "", "Listen address (host:port). Default is :{podcast_feed.port}.")
+	publicURL := fs.String("public-url", "", "Public base URL used for enclosure links (default app.public_url)")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	cfg := jctx.Config
+	if *listen == "" {
+		port := cfg.PodcastFeedPort
+		if port == 0 {
+			port = 8086
+		}
+		*listen = fmt.Sprintf(":%d", port)
+	}
+
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.PublicURL), "/")
+	if baseURL == "" {
+		baseURL = strings.TrimRight(strings.TrimSpace(*publicURL), "/")
+	}
+	if baseURL == "" {
+		return errors.New("missing public URL (set app.public_url or pass --public-url)")
+	}
+
+	mux := http.NewServeMux()
+	registerPodcastFeedRoutes(mux, jctx, baseURL)
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           httpLoggingMiddleware(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		utils.Info("podcast server listen", "listen", *listen, "feed_url", baseURL+"/podcast/feed.xml")
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+		return nil
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// registerPodcastFeedRoutes adds the public audio podcast endpoints:
+// - /podcast/feed.xml          RSS 2.0 + iTunes feed of YouTube-approved episodes
+// - /podcast/audio/{id}.mp3    episode audio (music-bed mix when present, otherwise the narration mp3)
+// - /podcast/image/{id}.jpg    episode artwork (the video thumbnail)
+func registerPodcastFeedRoutes(mux *http.ServeMux, jctx jobs.JobContext, baseURL string) {
+	cfg := jctx.Config
+
+	mux.HandleFunc("/podcast/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		episodes, err := listPodcastFeedEpisodes(r.Context(), jctx, baseURL)
+		if err != nil {
+			utils.Error("podcast feed query failed", "err", err)
+			http.Error(w, "feed unavailable", http.StatusInternalServerError)
+			return
+		}
+		channel := podcastfeed.Channel{
+			Title:       cfg.PodcastFeedTitle,
+			Link:        baseURL,
+			FeedURL:     baseURL + "/podcast/feed.xml",
+			Description: cfg.PodcastFeedDescription,
+			Language:    cfg.PodcastFeedLanguage,
+			Author:      cfg.PodcastFeedAuthor,
+			OwnerName:   cfg.PodcastFeedAuthor,
+			OwnerEmail:  cfg.PodcastFeedEmail,
+			ImageURL:    cfg.PodcastFeedImageURL,
+			Category:    cfg.PodcastFeedCategory,
+			Explicit:    cfg.PodcastFeedExplicit,
+		}
+		body, err := podcastfeed.Render(channel, episodes)
+		if err != nil {
+			utils.Error("podcast feed render failed", "err", err)
+			http.Error(w, "feed unavailable", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_, _ = w.Write(body)
+	})
+
+	mux.HandleFunc("/podcast/audio/", func(w http.ResponseWriter, r *http.Request) {
+		content, meta, ok := podcastFeedContent(w, r, "/podcast/audio/", ".mp3", jctx)
+		if !ok {
+			return
+		}
+		artifact, ok := podcastAudioArtifact(cfg.BaseOutputFolder, meta)
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if err := ensureLocalArtifact(artifact); err != nil {
+			utils.Warn("podcast audio fetch failed", "content_id", content.ID, "err", err)
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		http.ServeFile(w, r, artifact.Path)
+	})
+
+	mux.HandleFunc("/podcast/image/", func(w http.ResponseWriter, r *http.Request) {
+		content, meta, ok := podcastFeedContent(w, r, "/podcast/image/", ".jpg", jctx)
+		if !ok {
+			return
+		}
+		artifact, ok := podcastImageArtifact(cfg.BaseOutputFolder, meta)
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if err := ensureLocalArtifact(artifact); err != nil {
+			utils.Warn("podcast image fetch failed", "content_id", content.ID, "err", err)
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		http.ServeFile(w, r, artifact.Path)
+	})
+}
+
+// podcastFeedContent parses /prefix/{id}{ext} and loads the content, refusing anything that is not
+// YouTube-approved so unreviewed episodes never leak through guessable URLs.
+func podcastFeedContent(w http.ResponseWriter, r *http.Request, prefix, ext string, jctx jobs.JobContext) (db.Content, map[string]any, bool) {
+	suffix := strings.TrimPrefix(r.URL.Path, prefix)
+	if !strings.HasSuffix(suffix, ext) {
+		http.Error(w, "not found", http.StatusNotFound)
+		return db.Content{}, nil, false
+	}
+	id, err := strconv.ParseInt(strings.TrimSuffix(suffix, ext), 10, 64)
+	if err != nil || id <= 0 {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return db.Content{}, nil, false
+	}
+	content, err := jctx.Store.GetContentByID(r.Context(), id)
+	if err != nil || content.ID == 0 {
+		http.Error(w, "not found", http.StatusNotFound)
+		return db.Content{}, nil, false
+	}
+	meta, err := utils.DecodeMeta(content.Meta)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return db.Content{}, nil, false
+	}
+	if approved, _ := utils.GetStatus(meta, "youtube_approved"); !approved {
+		http.Error(w, "not found", http.StatusNotFound)
+		return db.Content{}, nil, false
+	}
+	return content, meta, true
+}
+
+func listPodcastFeedEpisodes(ctx context.Context, jctx jobs.JobContext, baseURL string) ([]podcastfeed.Episode, error) {
+	limit := jctx.Config.PodcastFeedLimit
+	if limit <= 0 {
+		limit = 100
+	}
+	trueFlags := db.StatusTrueCondition([]string{"mp3_generated", "thumbnail_generated", "youtube_approved"})
+	contents, err := jctx.Store.QueryContents(ctx, fmt.Sprintf(`
+		SELECT id, title, status, type, sentences, count, meta, archive, created_at, updated_at
+		FROM contents
+		WHERE type = 'gemini.payload'
+		  AND %s
+		ORDER BY id DESC
+		LIMIT $1
+	`, trueFlags), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := make([]podcastfeed.Episode, 0, len(contents))
+	for _, content := range contents {
+		meta, err := utils.DecodeMeta(content.Meta)
+		if err != nil {
+			utils.Warn("podcast feed skipping content with invalid meta", "content_id", content.ID, "err", err)
+			continue
+		}
+		audio, ok := podcastAudioArtifact(jctx.Config.BaseOutputFolder, meta)
+		if !ok {
+			utils.Debug("podcast feed skipping content without audio meta", "content_id", content.ID)
+			continue
+		}
+		length := audio.Bytes
+		if length <= 0 {
+			if info, err := os.Stat(audio.Path); err == nil {
+				length = info.Size()
+			}
+		}
+		if length <= 0 {
+			utils.Debug("podcast feed skipping content without audio size", "content_id", content.ID)
+			continue
+		}
+
+		var duration time.Duration
+		if raw, ok := utils.GetValue(meta, "mp3s", "0", "duration"); ok {
+			if seconds, ok := raw.(float64); ok {
+				duration = time.Duration(seconds * float64(time.Second))
+			}
+		}
+
+		episode := podcastfeed.Episode{
+			GUID:        fmt.Sprintf("ai-things-content-%d", content.ID),
+			Title:       content.Title,
+			Description: podcastEpisodeDescription(meta),
+			AudioURL:    fmt.Sprintf("%s/podcast/audio/%d.mp3", baseURL, content.ID),
+			AudioType:   "audio/mpeg",
+			AudioLength: length,
+			Duration:    duration,
+			PublishedAt: content.CreatedAt,
+		}
+		if videoID, _ := meta["video_id.v1"].(string); videoID != "" {
+			episode.Link = "https://www.youtube.com/watch?v=" + videoID
+		}
+		if _, ok := podcastImageArtifact(jctx.Config.BaseOutputFolder, meta); ok {
+			episode.ImageURL = fmt.Sprintf("%s/podcast/image/%d.jpg", baseURL, content.ID)
+		}
+		episodes = append(episodes, episode)
+	}
+	return episodes, nil
+}
+
+func podcastEpisodeDescription(meta map[string]any) string {
+	text, err := utils.ExtractTextFromMeta(meta)
+	if err != nil {
+		text = ""
+	}
+	text = podcastfeed.CleanText(text)
+
+	// Credit the background music when one was mixed in (most bed licenses require attribution).
+	if musicMeta, ok := utils.GetMap(meta, "music"); ok {
+		title, _ := musicMeta["title"].(string)
+		artist, _ := musicMeta["artist"].(string)
+		license, _ := musicMeta["license"].(string)
+		sourceURL, _ := musicMeta["source_url"].(string)
+		if strings.TrimSpace(title) != "" {
+			credit := "Music: " + title
+			if artist != "" {
+				credit += " by " + artist
+			}
+			if license != "" {
+				credit += " (" + license + ")"
+			}
+			if sourceURL != "" {
+				credit += " " + sourceURL
+			}
+			if text != "" {
+				text += "\n\n"
+			}
+			text += credit
+		}
+	}
+	return text
+}
+
+// podcastArtifact is a generated file plus where it was rendered, so it can be fetched on demand.
+type podcastArtifact struct {
+	Path     string
+	Hostname string
+	SHA256   string
+	Bytes    int64
+}
+
+// podcastAudioArtifact prefers the music-bed mix (meta.music) and falls back to the narration mp3 (meta.mp3s[0]).
+func podcastAudioArtifact(baseOut string, meta map[string]any) (podcastArtifact, bool) {
+	if musicMeta, ok := utils.GetMap(meta, "music"); ok {
+		if filename, _ := musicMeta["filename"].(string); strings.TrimSpace(filename) != "" {
+			return newPodcastArtifact(filepath.Join(baseOut, "mixed", filename), musicMeta), true
+		}
+	}
+	mp3Meta, ok := utils.GetMap(meta, "mp3s", "0")
+	if !ok {
+		return podcastArtifact{}, false
+	}
+	filename, _ := mp3Meta["mp3"].(string)
+	if strings.TrimSpace(filename) == "" {
+		return podcastArtifact{}, false
+	}
+	return newPodcastArtifact(filepath.Join(baseOut, "mp3", filename), mp3Meta), true
+}
+
+func podcastImageArtifact(baseOut string, meta map[string]any) (podcastArtifact, bool) {
+	thumbnail, ok := utils.GetMap(meta, "thumbnail")
+	if !ok {
+		return podcastArtifact{}, false
+	}
+	filename, _ := thumbnail["filename"].(string)
+	if strings.TrimSpace(filename) == "" {
+		return podcastArtifact{}, false
+	}
+	return newPodcastArtifact(filepath.Join(baseOut, "images", filename), thumbnail), true
+}
+
+func newPodcastArtifact(path string, m map[string]any) podcastArtifact {
+	artifact := podcastArtifact{Path: path}
+	artifact.Hostname, _ = m["hostname"].(string)
+	artifact.SHA256, _ = m["sha256"].(string)
+	if bytes, ok := m["bytes"].(float64); ok {
+		artifact.Bytes = int64(bytes)
+	}
+	return artifact
+}
+
+// ensureLocalArtifact rsyncs the artifact from the host that rendered it when it is missing locally.
+func ensureLocalArtifact(artifact podcastArtifact) error {
+	if utils.FileExists(artifact.Path) {
+		return nil
+	}
+	host := strings.TrimSpace(artifact.Hostname)
+	if host == "" {
+		return fmt.Errorf("%s missing locally and no render host recorded", artifact.Path)
+	}
+	if err := utils.EnsureDir(filepath.Dir(artifact.Path)); err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("rsync -ravp %s:%s %s", host, utils.ShellEscape(artifact.Path), utils.ShellEscape(artifact.Path))
+	if _, err := utils.RunCommand(cmd); err != nil {
+		return err
+	}
+	if artifact.SHA256 != "" {
+		haveSHA, err := utils.SHA256File(artifact.Path)
+		if err != nil {
+			return err
+		}
+		if haveSHA != artifact.SHA256 {
+			return fmt.Errorf("checksum mismatch after fetch (want=%s have=%s)", artifact.SHA256, haveSHA)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"ai-things/manager-go/internal/jobs"
+	"ai-things/manager-go/internal/prompts"
+	"ai-things/manager-go/internal/utils"
+)
+
+func runPromptList(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Prompt:List", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	library := jobs.PromptLibrary(jctx.Config)
+	templates, err := library.List()
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		fmt.Printf("no prompt templates in %s\n", library.Dir)
+		return nil
+	}
+
+	// Mark the version each name resolves to (pinned, else latest).
+	for _, t := range templates {
+		active, err := library.Lookup(t.Name, 0)
+		marker := " "
+		if err == nil && active.Version == t.Version {
+			marker = "*"
+		}
+		fmt.Printf("%s %-16s v%-3d %s\n", marker, t.Name, t.Version, t.Path)
+	}
+	return nil
+}
+
+func runPromptRender(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Prompt:Render", flag.ContinueOnError)
+	version := fs.Int("version", 0, "Template version (default: pinned or latest)")
+	subject := fs.String("subject", "", "Subject variable")
+	length := fs.String("length", "", "Length variable (e.g. \"6 to 10\")")
+	language := fs.String("language", "", "Language variable")
+	tone := fs.String("tone", "", "Tone variable")
+	text := fs.String("text", "", "Text variable")
+	textFile := fs.String("text-file", "", "Read the text variable from a file")
+	contentID := fs.Int64("content-id", 0, "Use the narration text of this content as the text variable")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{
+		"version": true, "subject": true, "length": true, "language": true,
+		"tone": true, "text": true, "text-file": true, "content-id": true,
+	})
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	name := strings.TrimSpace(strings.Join(positionalArgs, " "))
+	if name == "" {
+		return errors.New("template name is required")
+	}
+
+	vars := prompts.Vars{
+		Subject:  *subject,
+		Length:   *length,
+		Language: *language,
+		Tone:     *tone,
+		Text:     *text,
+	}
+	if *textFile != "" {
+		data, err := os.ReadFile(*textFile)
+		if err != nil {
+			return err
+		}
+		vars.Text = string(data)
+	}
+	if *contentID != 0 {
+		content, err := jctx.Store.GetContentByID(ctx, *contentID)
+		if err != nil {
+			return err
+		}
+		meta, err := utils.DecodeMeta(content.Meta)
+		if err != nil {
+			return err
+		}
+		if vars.Text, err = utils.ExtractTextFromMeta(meta); err != nil {
+			return err
+		}
+	}
+
+	prompt, err := jobs.PromptLibrary(jctx.Config).Render(name, *version, vars)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("# %s v%d (%s)\n", prompt.Name, prompt.Version, prompt.Path)
+	fmt.Printf("# sha256 %s\n", prompt.SHA256)
+	if prompt.System != "" {
+		fmt.Println("## SYSTEM")
+		fmt.Println(prompt.System)
+	}
+	fmt.Println("## USER")
+	fmt.Println(prompt.User)
+	return nil
+}
@@ -0,0 +1,503 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/feeds"
+	"ai-things/manager-go/internal/fetcher"
+	"ai-things/manager-go/internal/jobs"
+	"ai-things/manager-go/internal/readable"
+	"ai-things/manager-go/internal/utils"
+)
+
+// runRssSubscribe adds a feed, or refreshes a known one from the feed (and --title) and resumes it.
+func runRssSubscribe(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Rss:Subscribe", flag.ContinueOnError)
+	title := fs.String("title", "", "Title to store instead of the feed's own")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"title": true})
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	if len(positionalArgs) == 0 {
+		return errors.New("url is required")
+	}
+	rawURL := positionalArgs[0]
+	parsed, err := url.ParseRequestURI(rawURL)
+	if err != nil || parsed.Scheme == "" {
+		return fmt.Errorf("invalid url: %s", rawURL)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := feeds.Fetch(ctx, client, rawURL, "", "")
+	if err != nil {
+		return fmt.Errorf("rss fetch failed: %w", err)
+	}
+	feed, err := feeds.Parse(resp.Body)
+	if err != nil {
+		return err
+	}
+	if *title != "" {
+		feed.Title = strings.TrimSpace(*title)
+	}
+
+	now := time.Now()
+	sub := db.Subscription{
+		FeedURL:       rawURL,
+		Title:         optionalString(feed.Title),
+		Description:   optionalString(feed.Description),
+		SiteURL:       optionalString(feed.Link),
+		LastFetchedAt: &now,
+		LastBuildDate: feed.Updated,
+		IsActive:      true,
+	}
+	id, err := jctx.Store.UpsertSubscription(ctx, sub)
+	if err != nil {
+		return err
+	}
+	if err := jctx.Store.SetSubscriptionActive(ctx, id, true); err != nil {
+		return err
+	}
+	fmt.Printf("subscribed %d %s (%s, %d items)\n", id, rawURL, feed.Title, len(feed.Items))
+	return nil
+}
+
+func runRssList(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Rss:List", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	subscriptions, err := jctx.Store.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		fmt.Println("no subscriptions")
+		return nil
+	}
+	for _, sub := range subscriptions {
+		state := "active"
+		if !sub.IsActive {
+			state = "paused"
+		}
+		fetched := "never"
+		if sub.LastFetchedAt != nil {
+			fetched = sub.LastFetchedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%5d %-6s failures=%-2d fetched=%-16s %s\n", sub.ID, state, sub.FailureCount, fetched, derefString(sub.Title))
+		fmt.Printf("      %s\n", sub.FeedURL)
+		if sub.LastError != nil {
+			fmt.Printf("      last error: %s\n", *sub.LastError)
+		}
+	}
+	return nil
+}
+
+func runRssPause(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	return setSubscriptionsActive(ctx, jctx, "Rss:Pause", args, false)
+}
+
+func runRssResume(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	return setSubscriptionsActive(ctx, jctx, "Rss:Resume", args, true)
+}
+
+func setSubscriptionsActive(ctx context.Context, jctx jobs.JobContext, name string, args []string, active bool) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, nil)
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	if len(positionalArgs) == 0 {
+		return errors.New("subscription id or url is required")
+	}
+	for _, arg := range positionalArgs {
+		sub, err := findSubscription(ctx, jctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := jctx.Store.SetSubscriptionActive(ctx, sub.ID, active); err != nil {
+			return err
+		}
+		state := "paused"
+		if active {
+			state = "resumed"
+		}
+		fmt.Printf("%s %d %s\n", state, sub.ID, sub.FeedURL)
+	}
+	return nil
+}
+
+func runRssRemove(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Rss:Remove", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, nil)
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	if len(positionalArgs) == 0 {
+		return errors.New("subscription id or url is required")
+	}
+	for _, arg := range positionalArgs {
+		sub, err := findSubscription(ctx, jctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := jctx.Store.DeleteSubscription(ctx, sub.ID); err != nil {
+			return err
+		}
+		fmt.Printf("removed %d %s\n", sub.ID, sub.FeedURL)
+	}
+	return nil
+}
+
+// runRssImport subscribes to every feed of an OPML file. Titles and site urls come from the file;
+// the feeds themselves are first fetched by Rss:FetchHtml. Known feeds keep their paused state.
+func runRssImport(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Rss:Import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "List the feeds without storing them")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, nil)
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	if len(positionalArgs) == 0 {
+		return errors.New("opml file is required")
+	}
+	data, err := os.ReadFile(positionalArgs[0])
+	if err != nil {
+		return err
+	}
+	outlines, err := feeds.ParseOPML(data)
+	if err != nil {
+		return err
+	}
+
+	imported, skipped := 0, 0
+	for _, outline := range outlines {
+		if parsed, err := url.ParseRequestURI(outline.FeedURL); err != nil || parsed.Scheme == "" {
+			utils.Warn("skipping invalid feed url", "url", outline.FeedURL)
+			skipped++
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("%s  %s\n", outline.FeedURL, outline.Title)
+			continue
+		}
+		id, err := jctx.Store.UpsertSubscription(ctx, db.Subscription{
+			FeedURL:  outline.FeedURL,
+			Title:    optionalString(outline.Title),
+			SiteURL:  optionalString(outline.SiteURL),
+			IsActive: true,
+		})
+		if err != nil {
+			return err
+		}
+		utils.Debug("imported subscription", "id", id, "url", outline.FeedURL)
+		imported++
+	}
+	fmt.Printf("imported %d feeds, skipped %d\n", imported, skipped)
+	return nil
+}
+
+// runRssExport writes all subscriptions as OPML to a file, or stdout without one.
+func runRssExport(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Rss:Export", flag.ContinueOnError)
+	activeOnly := fs.Bool("active", false, "Only export active feeds")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, nil)
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	subscriptions, err := jctx.Store.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	var outlines []feeds.Outline
+	for _, sub := range subscriptions {
+		if *activeOnly && !sub.IsActive {
+			continue
+		}
+		outlines = append(outlines, feeds.Outline{
+			Title:   derefString(sub.Title),
+			FeedURL: sub.FeedURL,
+			SiteURL: derefString(sub.SiteURL),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := feeds.WriteOPML(&buf, "ai-things subscriptions", outlines); err != nil {
+		return err
+	}
+	if len(positionalArgs) == 0 {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(positionalArgs[0], buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d feeds to %s\n", len(outlines), positionalArgs[0])
+	return nil
+}
+
+// findSubscription resolves a subscription id or feed url.
+func findSubscription(ctx context.Context, jctx jobs.JobContext, arg string) (db.Subscription, error) {
+	var sub db.Subscription
+	var err error
+	if id, convErr := strconv.ParseInt(arg, 10, 64); convErr == nil {
+		sub, err = jctx.Store.GetSubscriptionByID(ctx, id)
+	} else {
+		sub, err = jctx.Store.GetSubscriptionByURL(ctx, arg)
+	}
+	if err != nil {
+		return db.Subscription{}, err
+	}
+	if sub.ID == 0 {
+		return db.Subscription{}, fmt.Errorf("subscription not found: %s", arg)
+	}
+	return sub, nil
+}
+
+// rssFetchStats counts the outcome of one Rss:FetchHtml run.
+type rssFetchStats struct {
+	feeds, notModified, feedErrors, deactivated int
+	skipped, stored, itemErrors                 int
+}
+
+func runRssFetchHtml(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Rss:FetchHtml", flag.ContinueOnError)
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	subscriptions, err := jctx.Store.ListActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A failing feed or article is recorded and skipped; only database errors stop the run.
+	client := &http.Client{Timeout: 30 * time.Second}
+	var stats rssFetchStats
+	pending := map[string]feedArticle{}
+	var urls []string
+	for _, sub := range subscriptions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		stats.feeds++
+		items, err := fetchSubscription(ctx, jctx, client, sub, &stats)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if _, ok := pending[item.URL]; ok {
+				continue
+			}
+			existing, err := jctx.Store.GetCollectionByURL(ctx, item.URL)
+			if err != nil {
+				return err
+			}
+			if existing.HTMLContent != "" || !collectionFetchRetryable(existing, jctx.Config.RssItemMaxAttempts) {
+				stats.skipped++
+				continue
+			}
+			pending[item.URL] = feedArticle{item: item, existing: existing}
+			urls = append(urls, item.URL)
+		}
+	}
+
+	var mu sync.Mutex
+	err = jobs.NewFetcher(jctx.Config).FetchAll(ctx, urls, func(rawURL string, page fetcher.Page, fetchErr error) error {
+		article := pending[rawURL]
+		if err := storeFeedArticle(ctx, jctx, article, page, fetchErr); err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if fetchErr != nil {
+			stats.itemErrors++
+			utils.Warn("rss item failed", "url", rawURL, "status", fetcher.Status(fetchErr), "err", fetchErr)
+			return nil
+		}
+		stats.stored++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	utils.Info("rss fetch done",
+		"feeds", stats.feeds,
+		"not_modified", stats.notModified,
+		"feed_errors", stats.feedErrors,
+		"deactivated", stats.deactivated,
+		"skipped", stats.skipped,
+		"stored", stats.stored,
+		"item_errors", stats.itemErrors,
+	)
+	return nil
+}
+
+// feedArticle is a feed item queued for fetching, with its collection row if one exists.
+type feedArticle struct {
+	item     feeds.Item
+	existing db.Collection
+}
+
+// collectionFetchRetryable reports whether an article without HTML should be fetched (again):
+// never tried, or failed transiently fewer than maxAttempts times.
+func collectionFetchRetryable(c db.Collection, maxAttempts int) bool {
+	switch c.FetchStatus {
+	case "":
+		return true
+	case "disallowed", "too_large", "content_type":
+		return false
+	case "http_error":
+		if c.HTTPStatus != nil && *c.HTTPStatus >= 400 && *c.HTTPStatus < 500 &&
+			*c.HTTPStatus != http.StatusRequestTimeout && *c.HTTPStatus != http.StatusTooManyRequests {
+			return false
+		}
+	}
+	return maxAttempts <= 0 || c.FetchAttempts < maxAttempts
+}
+
+// fetchSubscription fetches and parses one feed and returns its items with a URL. Fetch and parse
+// failures are recorded on the subscription (and may deactivate it) and yield no items; the
+// returned error is a database error.
+func fetchSubscription(ctx context.Context, jctx jobs.JobContext, client *http.Client, sub db.Subscription, stats *rssFetchStats) ([]feeds.Item, error) {
+	resp, err := feeds.Fetch(ctx, client, sub.FeedURL, derefString(sub.ETag), derefString(sub.LastModified))
+	var feed feeds.Feed
+	if err == nil && !resp.NotModified {
+		feed, err = feeds.Parse(resp.Body)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		stats.feedErrors++
+		deactivated, dbErr := jctx.Store.RecordSubscriptionFailure(ctx, sub.ID, err.Error(), jctx.Config.RssMaxFailures)
+		if dbErr != nil {
+			return nil, dbErr
+		}
+		utils.Warn("rss feed failed", "id", sub.ID, "url", sub.FeedURL, "failures", sub.FailureCount+1, "err", err)
+		if deactivated {
+			stats.deactivated++
+			utils.Warn("rss feed deactivated", "id", sub.ID, "url", sub.FeedURL, "failures", sub.FailureCount+1)
+		}
+		return nil, nil
+	}
+
+	fetched := db.Subscription{ID: sub.ID, LastBuildDate: feed.Updated}
+	if resp.NotModified {
+		stats.notModified++
+		utils.Debug("rss feed not modified", "url", sub.FeedURL)
+	} else {
+		// An empty validator clears the stored one, so a feed that stops sending it is refetched.
+		fetched.ETag = &resp.ETag
+		fetched.LastModified = &resp.LastModified
+	}
+	if err := jctx.Store.RecordSubscriptionFetched(ctx, fetched); err != nil {
+		return nil, err
+	}
+
+	var items []feeds.Item
+	for _, item := range feed.Items {
+		if item.URL != "" {
+			items = append(items, item)
+		}
+	}
+	utils.Debug("rss feed fetched", "url", sub.FeedURL, "items", len(items))
+	return items, nil
+}
+
+// storeFeedArticle stores a fetched article as a collection, or records the failed fetch on it.
+func storeFeedArticle(ctx context.Context, jctx jobs.JobContext, article feedArticle, page fetcher.Page, fetchErr error) error {
+	item := article.item
+	var httpStatus *int
+	if page.StatusCode != 0 {
+		httpStatus = &page.StatusCode
+	}
+	if fetchErr == nil && strings.TrimSpace(page.Body) == "" {
+		fetchErr = errors.New("empty body")
+	}
+	if fetchErr != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return jctx.Store.RecordCollectionFetchFailure(ctx, db.Collection{
+			URL:         item.URL,
+			Title:       item.Title,
+			Language:    "en",
+			FetchStatus: fetcher.Status(fetchErr),
+			FetchError:  fetchErr.Error(),
+			HTTPStatus:  httpStatus,
+		})
+	}
+
+	extracted := readable.Extract(page.Body)
+	title := item.Title
+	if title == "" {
+		title = extracted.Title
+	}
+	published := extracted.Published
+	if published == nil {
+		published = item.Published
+	}
+	coll := db.Collection{
+		ID:          article.existing.ID,
+		URL:         item.URL,
+		Title:       title,
+		Language:    "en",
+		HTMLContent: page.Body,
+		TextContent: extracted.Text,
+		Byline:      extracted.Byline,
+		PublishedAt: published,
+		FetchedAt:   time.Now(),
+		HTTPStatus:  httpStatus,
+	}
+	if coll.ID != 0 {
+		return jctx.Store.UpdateCollectionContent(ctx, coll)
+	}
+	return jctx.Store.InsertCollection(ctx, coll)
+}
+
+// optionalString is nil for an empty string, for nullable columns.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
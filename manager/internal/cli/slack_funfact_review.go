@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/jobs"
+	"ai-things/manager-go/internal/slack"
+	"ai-things/manager-go/internal/utils"
+)
+
+// handleSlackFunFactReviewReaction records a human decision on a fun fact flagged by
+// job:ReviewFunFact. It returns false when the message is not a fun fact review thread, so the
+// caller can try the other review handlers.
+func handleSlackFunFactReviewReaction(
+	ctx context.Context,
+	jctx jobs.JobContext,
+	client *http.Client,
+	teamID string,
+	channelID string,
+	messageTS string,
+	reaction string,
+	userID string,
+) bool {
+	r := strings.TrimSpace(reaction)
+	var decision string
+	switch r {
+	case "thumbsup", "+1":
+		decision = "approved"
+	case "thumbsdown", "-1":
+		decision = "rejected"
+	default:
+		return false
+	}
+
+	content, err := jctx.Store.FindContentBySlackThread(ctx, "slack_funfact_review_request", teamID, channelID, messageTS)
+	if err != nil {
+		utils.Warn("slack funfact review lookup failed", "team_id", teamID, "channel", channelID, "ts", messageTS, "err", err)
+		return false
+	}
+	if content.ID == 0 {
+		return false
+	}
+	utils.Info("slack funfact review reaction", "content_id", content.ID, "reaction", r, "decision", decision, "user_id", userID)
+
+	meta, err := utils.DecodeMeta(content.Meta)
+	if err != nil {
+		utils.Warn("slack funfact review decode meta failed", "content_id", content.ID, "err", err)
+		return true
+	}
+
+	review, _ := utils.GetMap(meta, "review")
+	if review == nil {
+		review = map[string]any{}
+	}
+	review["human"] = map[string]any{
+		"decision":           decision,
+		"decided_at":         time.Now().Format(time.RFC3339),
+		"decided_by_user_id": userID,
+		"decided_reaction":   r,
+	}
+	meta["review"] = review
+
+	var statusKey, reply string
+	switch decision {
+	case "approved":
+		statusKey = "funfact_reviewed"
+		utils.SetStatus(meta, "funfact_reviewed", true)
+		utils.SetStatus(meta, "funfact_flagged", false)
+		utils.SetStatus(meta, "funfact_rejected", false)
+		reply = "Approved. Queued for TTS."
+	case "rejected":
+		statusKey = "funfact_rejected"
+		utils.SetStatus(meta, "funfact_rejected", true)
+		reply = "Rejected. This fun fact will not be narrated."
+	}
+
+	if err := jctx.Store.UpdateContentMetaStatus(ctx, content.ID, statusKey, meta); err != nil {
+		utils.Warn("slack funfact review update failed", "content_id", content.ID, "err", err)
+		return true
+	}
+	utils.Info("slack funfact review recorded", "content_id", content.ID, "decision", decision, "status_key", statusKey)
+
+	if decision == "approved" && jctx.Queue != nil {
+		payload, _ := json.Marshal(jobs.QueuePayload{ContentID: content.ID, Hostname: ""})
+		if err := jctx.Queue.Publish("funfact_reviewed", payload); err != nil {
+			utils.Warn("slack funfact reviewed publish failed", "content_id", content.ID, "err", err)
+		}
+	}
+
+	token, err := jctx.Store.GetSlackBotToken(ctx, teamID)
+	if err != nil || token == "" {
+		utils.Warn("slack no bot token", "team_id", teamID, "err", err)
+		return true
+	}
+	if err := slack.PostMessage(ctx, client, token, channelID, reply, messageTS); err != nil {
+		utils.Warn("slack funfact review reply failed", "content_id", content.ID, "channel", channelID, "err", err)
+	}
+	return true
+}
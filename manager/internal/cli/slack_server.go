@@ -230,6 +230,12 @@ func runSlackServe(ctx context.Context, jctx jobs.JobContext, args []string) err
 </html>`, id, id, mp4URL, mp4URL)))
 	})
 
+	if baseURL != "" {
+		registerPodcastFeedRoutes(mux, jctx, baseURL)
+	} else {
+		utils.Warn("podcast feed disabled; no public URL (set app.public_url or pass --public-url)")
+	}
+
 	mux.HandleFunc("/slack/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
 		_, _ = w.Write([]byte("ok\n"))
@@ -401,7 +407,21 @@ func runSlackServe(ctx context.Context, jctx jobs.JobContext, args []string) err
 						return
 					}
 
-					// Allow YouTube review approvals via plain text in the thread (fallback if reaction_added isn't delivered).
+					// Allow review approvals via plain text in the thread (fallback if reaction_added isn't delivered).
+					if reaction, ok := slackDecisionReaction(original); ok {
+						if handled := handleSlackFunFactReviewReaction(
+							context.Background(),
+							jctx,
+							client,
+							teamID,
+							channel,
+							threadTS,
+							reaction,
+							envelope.Event.User,
+						); handled {
+							return
+						}
+					}
 					if handled := handleSlackYouTubeReviewTextDecision(
 						context.Background(),
 						jctx,
@@ -443,7 +463,7 @@ func runSlackServe(ctx context.Context, jctx jobs.JobContext, args []string) err
 				}()
 				return
 			case "reaction_added":
-				// Handle approvals/rejections for YouTube review threads.
+				// Handle approvals/rejections for fun fact and YouTube review threads.
 				itemTS := envelope.Event.Item.TS
 				itemChannel := envelope.Event.Item.Channel
 				reaction := envelope.Event.Reaction
@@ -461,6 +481,9 @@ func runSlackServe(ctx context.Context, jctx jobs.JobContext, args []string) err
 					"user_id", userID,
 				)
 				go func() {
+					if handleSlackFunFactReviewReaction(context.Background(), jctx, client, teamID, itemChannel, itemTS, reaction, userID) {
+						return
+					}
 					handleSlackYouTubeReviewReaction(
 						context.Background(),
 						jctx,
@@ -872,38 +895,8 @@ func handleSlackYouTubeReviewTextDecision(
 	text string,
 	userID string,
 ) bool {
-	t := strings.ToLower(strings.TrimSpace(text))
-	if t == "" {
-		return false
-	}
-
-	approve := map[string]bool{
-		"approve":  true,
-		"approved": true,
-		"yes":      true,
-		"y":        true,
-		"ship it":  true,
-		"shipit":   true,
-		"+1":       true,
-		"👍":        true,
-	}
-	reject := map[string]bool{
-		"reject":   true,
-		"rejected": true,
-		"no":       true,
-		"n":        true,
-		"redo":     true,
-		"-1":       true,
-		"👎":        true,
-	}
-
-	var reaction string
-	switch {
-	case approve[t]:
-		reaction = "thumbsup"
-	case reject[t]:
-		reaction = "thumbsdown"
-	default:
+	reaction, ok := slackDecisionReaction(text)
+	if !ok {
 		return false
 	}
 
@@ -964,7 +957,7 @@ func handleSlackImageUpload(
 		return false
 	}
 
-	content, err := jctx.Store.FindContentBySlackImageThread(ctx, teamID, channelID, threadTS)
+	content, err := jctx.Store.FindContentBySlackThread(ctx, "slack_image_request", teamID, channelID, threadTS)
 	if err != nil {
 		utils.Warn("slack image: lookup failed", "team_id", teamID, "channel", channelID, "thread_ts", threadTS, "err", err)
 		return true
@@ -1048,3 +1041,35 @@ func handleSlackImageUpload(
 	_ = slack.PostMessage(ctx, client, token, channelID, fmt.Sprintf("Saved image as %s and marked thumbnail_generated=true.", filename), threadTS)
 	return true
 }
+
+// slackDecisionReaction maps a plain-text approve/reject reply in a review thread to the
+// reaction it stands for ("thumbsup"/"thumbsdown").
+func slackDecisionReaction(text string) (string, bool) {
+	t := strings.ToLower(strings.TrimSpace(text))
+	approve := map[string]bool{
+		"approve":  true,
+		"approved": true,
+		"yes":      true,
+		"y":        true,
+		"ship it":  true,
+		"shipit":   true,
+		"+1":       true,
+		"👍":        true,
+	}
+	reject := map[string]bool{
+		"reject":   true,
+		"rejected": true,
+		"no":       true,
+		"n":        true,
+		"redo":     true,
+		"-1":       true,
+		"👎":        true,
+	}
+	switch {
+	case approve[t]:
+		return "thumbsup", true
+	case reject[t]:
+		return "thumbsdown", true
+	}
+	return "", false
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/jobs"
+	"ai-things/manager-go/internal/utils"
+)
+
+// runSubjectList prints subjects with the weight jobs.SelectSubject gives them now.
+func runSubjectList(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Subject:List", flag.ContinueOnError)
+	all := fs.Bool("all", false, "Include inactive subjects")
+	sortBy := fs.String("sort", "weight", "Order: weight, used, score, name or id")
+	limit := fs.Int("limit", 50, "Max subjects to print (0 for all)")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	subjects, err := jctx.Store.ListSubjects(ctx, !*all)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	weights := make(map[int64]float64, len(subjects))
+	total := 0.0
+	for _, subject := range subjects {
+		weight := jobs.SubjectWeight(jctx.Config, subject, now)
+		if jobs.SubjectInCooldown(jctx.Config, subject, now) {
+			weight = 0
+		}
+		weights[subject.ID] = weight
+		total += weight
+	}
+
+	switch *sortBy {
+	case "weight":
+		sort.SliceStable(subjects, func(i, j int) bool { return weights[subjects[i].ID] > weights[subjects[j].ID] })
+	case "used":
+		sort.SliceStable(subjects, func(i, j int) bool { return subjects[i].PodcastsCount > subjects[j].PodcastsCount })
+	case "score":
+		score := func(subject db.Subject) float64 {
+			if subject.Score == nil {
+				return 1
+			}
+			return *subject.Score
+		}
+		sort.SliceStable(subjects, func(i, j int) bool { return score(subjects[i]) > score(subjects[j]) })
+	case "name":
+		sort.SliceStable(subjects, func(i, j int) bool { return subjects[i].Subject < subjects[j].Subject })
+	case "id":
+	default:
+		return fmt.Errorf("unknown sort %q", *sortBy)
+	}
+	if *limit > 0 && len(subjects) > *limit {
+		subjects = subjects[:*limit]
+	}
+
+	fmt.Printf("strategy=%s cooldown=%gh\n", jctx.Config.SubjectStrategy, jctx.Config.SubjectCooldownHours)
+	for _, subject := range subjects {
+		state := "active"
+		switch {
+		case !subject.IsActive:
+			state = "off"
+		case jobs.SubjectInCooldown(jctx.Config, subject, now):
+			state = "cooling"
+		}
+		lastUsed := "never"
+		if subject.LastUsedAt != nil {
+			lastUsed = subject.LastUsedAt.Format("2006-01-02")
+		}
+		chance := 0.0
+		if total > 0 {
+			chance = weights[subject.ID] / total * 100
+		}
+		score := "-"
+		if subject.Score != nil {
+			score = fmt.Sprintf("%.2f/%d", *subject.Score, subject.ScoreVideos)
+		}
+		fmt.Printf("%6d %-7s used=%-3d last=%-10s score=%-7s weight=%.3f (%5.2f%%) %s\n",
+			subject.ID, state, subject.PodcastsCount, lastUsed, score, weights[subject.ID], chance, strings.TrimSpace(subject.Subject))
+	}
+	return nil
+}
+
+func runSubjectActivate(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	return setSubjectsActive(ctx, jctx, "Subject:Activate", args, true)
+}
+
+func runSubjectDeactivate(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	return setSubjectsActive(ctx, jctx, "Subject:Deactivate", args, false)
+}
+
+func setSubjectsActive(ctx context.Context, jctx jobs.JobContext, name string, args []string, active bool) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, nil)
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	if len(positionalArgs) == 0 {
+		return errors.New("subject id or name is required")
+	}
+	for _, arg := range positionalArgs {
+		subject, err := findSubject(ctx, jctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := jctx.Store.SetSubjectActive(ctx, subject.ID, active); err != nil {
+			return err
+		}
+		state := "deactivated"
+		if active {
+			state = "activated"
+		}
+		fmt.Printf("%s %d %s\n", state, subject.ID, subject.Subject)
+	}
+	return nil
+}
+
+// runSubjectAdd adds a subject, or reactivates an existing one with the same name; --keywords
+// replaces its keywords.
+func runSubjectAdd(ctx context.Context, jctx jobs.JobContext, args []string) error {
+	fs := flag.NewFlagSet("Subject:Add", flag.ContinueOnError)
+	keywords := fs.String("keywords", "", "Comma-separated keywords")
+	verbose := fs.Bool("verbose", utils.Verbose, "Verbose logging")
+	flagArgs, positionalArgs := splitInterspersedFlagArgs(args, map[string]bool{"keywords": true})
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+	utils.ConfigureLogging(*verbose)
+
+	name := strings.ToLower(strings.Join(strings.Fields(strings.Join(positionalArgs, " ")), " "))
+	if name == "" {
+		return errors.New("subject name is required")
+	}
+
+	subject, err := jctx.Store.GetSubjectByNameFold(ctx, name)
+	if err != nil {
+		return err
+	}
+	created := subject.ID == 0
+	if created {
+		if err := jctx.Store.InsertSubject(ctx, name); err != nil {
+			return err
+		}
+		if subject, err = jctx.Store.GetSubjectByNameFold(ctx, name); err != nil {
+			return err
+		}
+	} else if !subject.IsActive {
+		if err := jctx.Store.SetSubjectActive(ctx, subject.ID, true); err != nil {
+			return err
+		}
+	}
+	if *keywords != "" {
+		var parts []string
+		for _, keyword := range strings.Split(*keywords, ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				parts = append(parts, keyword)
+			}
+		}
+		if err := jctx.Store.SetSubjectKeywords(ctx, subject.ID, optionalString(strings.Join(parts, ", "))); err != nil {
+			return err
+		}
+	}
+
+	if created {
+		fmt.Printf("added %d %s\n", subject.ID, subject.Subject)
+	} else {
+		fmt.Printf("updated %d %s\n", subject.ID, subject.Subject)
+	}
+	return nil
+}
+
+// findSubject resolves a subject id or name (case-insensitive).
+func findSubject(ctx context.Context, jctx jobs.JobContext, arg string) (db.Subject, error) {
+	var subject db.Subject
+	var err error
+	if id, convErr := strconv.ParseInt(arg, 10, 64); convErr == nil {
+		subject, err = jctx.Store.GetSubjectByID(ctx, id)
+	} else {
+		subject, err = jctx.Store.GetSubjectByNameFold(ctx, arg)
+	}
+	if err != nil {
+		return db.Subject{}, err
+	}
+	if subject.ID == 0 {
+		return db.Subject{}, fmt.Errorf("subject not found: %s", arg)
+	}
+	return subject, nil
+}
@@ -29,11 +29,124 @@ type Config struct {
 	TikTokUploadScript         string
 	Portnumber53APIKey         string
 	Portnumber53TimeoutSeconds int
+	Portnumber53URL            string
+	Portnumber53Model          string
+
+	// LLM provider per use case as "provider:model" (see LLMUseCases for the keys), plus retry
+	// and timeout settings shared by all providers.
+	LLMUseCases          map[string]string
+	LLMRetries           int
+	LLMRetryDelaySeconds int
+	LLMTimeoutSeconds    int
+	// LLMRepairAttempts is how often an invalid JSON answer is sent back to the model for repair.
+	LLMRepairAttempts int
+
+	// ReviewFunFact flags facts scoring below ReviewMinAccuracy (0-10), above ReviewMaxHallucinationRisk
+	// (low|medium|high) or with policy issues; ReviewSlack posts flagged facts to Slack for a human.
+	ReviewMinAccuracy          float64
+	ReviewMaxHallucinationRisk string
+	ReviewSlack                bool
+
+	// Semantic duplicate detection: a new fun fact whose embedding (llm use case "embed") is at least
+	// SimilarityThreshold (cosine) close to existing content is regenerated up to
+	// SimilarityRegenerations times, then rejected.
+	SimilarityCheck         bool
+	SimilarityThreshold     float64
+	SimilarityRegenerations int
+
+	// Subject selection for Ai:GenerateFunFacts (jobs.SelectSubject). SubjectStrategy is weighted,
+	// least_used, random or unused (never-used subjects only, the old behaviour). Subjects used
+	// within SubjectCooldownHours are skipped while others are available; the weights are
+	// exponents applied to the usage, freshness (half-life SubjectFreshnessDays since the subject
+	// was last seen in an article) and performance (subjects.score) factors.
+	SubjectStrategy          string
+	SubjectCooldownHours     float64
+	SubjectUsageWeight       float64
+	SubjectFreshnessWeight   float64
+	SubjectFreshnessDays     float64
+	SubjectPerformanceWeight float64
+
+	// YouTube Data API key for Youtube:UpdateMeta statistics, refreshed at most every
+	// YoutubeStatsMaxAgeHours. Videos younger than YoutubeScoreMinAgeDays do not count towards
+	// subject scores yet.
+	YoutubeAPIKey           string
+	YoutubeStatsMaxAgeHours float64
+	YoutubeScoreMinAgeDays  float64
+
+	// YoutubeUploader picks how job:UploadPodcastToYoutube uploads: script (paths.youtube_upload_script)
+	// or api (native resumable upload with the OAuth client in YoutubeClientSecretsFile and the
+	// refresh token Youtube:Auth writes to YoutubeTokenFile).
+	YoutubeUploader          string
+	YoutubeClientSecretsFile string
+	YoutubeTokenFile         string
+	YoutubeUploadChunkMB     int
+	YoutubeUploadRetries     int
+
+	// RssMaxFailures consecutive failed fetches deactivate a subscription (0 never deactivates).
+	// RssItemMaxAttempts caps retries of an article whose fetch keeps failing.
+	RssMaxFailures     int
+	RssItemMaxAttempts int
+
+	// Article fetcher (internal/fetcher): simultaneous requests, minimum delay between requests to
+	// one host, body size cap, per-request timeout and retries of transient failures.
+	FetchUserAgent          string
+	FetchConcurrency        int
+	FetchDomainDelaySeconds float64
+	FetchMaxBytes           int
+	FetchTimeoutSeconds     int
+	FetchRetries            int
+
+	// Prompt templates folder (<folder>/<name>/v<N>.tmpl) and pinned versions per template name.
+	PromptFolder string
+	PromptPins   map[string]int
+
+	// Transcription engine used by GenerateSrt (script|whisper_cpp|http|tts).
+	TranscribeEngine           string
+	TranscribeLanguage         string
+	TranscribeWhisperCppBinary string
+	TranscribeWhisperCppModel  string
+	TranscribeHTTPURL          string
+	TranscribeHTTPModel        string
+	TranscribeHTTPAPIKey       string
+	TranscribeHTTPTimeout      int
 
 	TTSOnnxModel string
 	TTSConfig    string
 	TTSVoice     string
 
+	// Background music bed mixed under the narration (empty library folder disables mixing).
+	MusicLibraryFolder string
+	MusicVolume        float64
+	MusicFadeSeconds   float64
+
+	// Subtitles: CorrectSubtitles flags transcripts whose alignment score with the script is below this.
+	SubtitlesMinAlignmentScore float64
+	// SubtitlesMode is "asr" (transcribe, then Fix/CorrectSubtitles) or "alignment" (time the known
+	// script from the TTS sentence segments and mark srt_fixed directly).
+	SubtitlesMode string
+	// Caption style profile (vertical|landscape) plus optional per-key overrides (0 keeps the profile value).
+	SubtitleStyle           string
+	SubtitleMaxCharsPerLine int
+	SubtitleMaxLines        int
+	SubtitleMinDuration     float64
+	SubtitleMaxDuration     float64
+	SubtitleMaxCPS          float64
+	// TranslateSubtitles target languages (e.g. es, pt-BR) and the Ollama model used (default ollama.model).
+	SubtitleTranslateLanguages []string
+	SubtitleTranslateModel     string
+
+	// Audio podcast RSS feed (served by Slack:Serve and Podcast:Serve).
+	PodcastFeedTitle       string
+	PodcastFeedDescription string
+	PodcastFeedAuthor      string
+	PodcastFeedEmail       string
+	PodcastFeedImageURL    string
+	PodcastFeedLanguage    string
+	PodcastFeedCategory    string
+	PodcastFeedExplicit    bool
+	PodcastFeedLimit       int
+	PodcastFeedPort        int
+
 	DBURL      string
 	DBHost     string
 	DBPort     int
@@ -52,6 +165,7 @@ type Config struct {
 	OllamaPort        int
 	OllamaModel       string
 	GeminiAPIKey      string
+	GeminiModel       string
 	TikTokAccessToken string
 	TikTokVideoPath   string
 
@@ -109,12 +223,47 @@ func Load() (Config, error) {
 	cfg.TTSConfig = ini.get("tts", "config_file")
 	cfg.TTSVoice = ini.get("tts", "voice")
 
+	cfg.MusicLibraryFolder = ini.get("music", "library_folder")
+	cfg.MusicVolume = ini.getFloatDefault("music", "volume", 0.15)
+	cfg.MusicFadeSeconds = ini.getFloatDefault("music", "fade_seconds", 3)
+
+	cfg.SubtitlesMinAlignmentScore = ini.getFloatDefault("subtitles", "min_alignment_score", 0.6)
+	cfg.SubtitlesMode = strings.ToLower(ini.getDefault("subtitles", "mode", "asr"))
+	cfg.SubtitleStyle = ini.getDefault("subtitles", "style", "vertical")
+	cfg.SubtitleMaxCharsPerLine = ini.getIntDefault("subtitles", "max_chars_per_line", 0)
+	cfg.SubtitleMaxLines = ini.getIntDefault("subtitles", "max_lines", 0)
+	cfg.SubtitleMinDuration = ini.getFloatDefault("subtitles", "min_duration", 0)
+	cfg.SubtitleMaxDuration = ini.getFloatDefault("subtitles", "max_duration", 0)
+	cfg.SubtitleMaxCPS = ini.getFloatDefault("subtitles", "max_cps", 0)
+	cfg.SubtitleTranslateLanguages = splitList(ini.get("subtitles", "translate_languages"))
+	cfg.SubtitleTranslateModel = ini.get("subtitles", "translate_model")
+
+	cfg.PodcastFeedTitle = ini.getDefault("podcast_feed", "title", "AI Things")
+	cfg.PodcastFeedDescription = ini.getDefault("podcast_feed", "description", "Short fun facts, narrated.")
+	cfg.PodcastFeedAuthor = ini.get("podcast_feed", "author")
+	cfg.PodcastFeedEmail = ini.get("podcast_feed", "email")
+	cfg.PodcastFeedImageURL = ini.get("podcast_feed", "image_url")
+	cfg.PodcastFeedLanguage = ini.getDefault("podcast_feed", "language", "en-us")
+	cfg.PodcastFeedCategory = ini.getDefault("podcast_feed", "category", "Education")
+	cfg.PodcastFeedExplicit = ini.getBoolDefault("podcast_feed", "explicit", false)
+	cfg.PodcastFeedLimit = ini.getIntDefault("podcast_feed", "limit", 100)
+	cfg.PodcastFeedPort = ini.getIntDefault("podcast_feed", "port", 8086)
+
 	cfg.SubtitleScript = ini.get("paths", "subtitle_script")
 	if cfg.SubtitleScript == "" && cfg.BaseAppFolder != "" {
 		py := pythonForProjectVenv(cfg.BaseAppFolder, "podcast")
 		cfg.SubtitleScript = fmt.Sprintf("%s %s", py, filepath.Join(cfg.BaseAppFolder, "podcast", "whisper.py"))
 	}
 
+	cfg.TranscribeEngine = ini.getDefault("transcribe", "engine", "script")
+	cfg.TranscribeLanguage = ini.getDefault("transcribe", "language", "en")
+	cfg.TranscribeWhisperCppBinary = ini.getDefault("transcribe", "whisper_cpp_binary", "whisper-cli")
+	cfg.TranscribeWhisperCppModel = ini.get("transcribe", "whisper_cpp_model")
+	cfg.TranscribeHTTPURL = ini.get("transcribe", "http_url")
+	cfg.TranscribeHTTPModel = ini.getDefault("transcribe", "http_model", "whisper-1")
+	cfg.TranscribeHTTPAPIKey = ini.get("transcribe", "http_api_key")
+	cfg.TranscribeHTTPTimeout = ini.getIntDefault("transcribe", "http_timeout_seconds", 600)
+
 	cfg.YoutubeUpload = ini.get("paths", "youtube_upload_script")
 	if cfg.YoutubeUpload == "" && cfg.BaseAppFolder != "" {
 		py := pythonForProjectVenv(cfg.BaseAppFolder, "auto-subtitles-generator")
@@ -137,6 +286,79 @@ func Load() (Config, error) {
 	cfg.OllamaPort = ini.getIntDefault("ollama", "port", 11434)
 	cfg.OllamaModel = ini.getDefault("ollama", "model", "llama3.2")
 	cfg.GeminiAPIKey = ini.get("gemini", "api_key")
+	cfg.GeminiModel = ini.getDefault("gemini", "model", "gemini-1.5-flash")
+	cfg.Portnumber53URL = ini.getDefault("portnumber53", "url", "https://ollama.portnumber53.com")
+	cfg.Portnumber53Model = ini.getDefault("portnumber53", "model", "llama3.3")
+
+	cfg.LLMRetries = ini.getIntDefault("llm", "retries", 2)
+	cfg.LLMRetryDelaySeconds = ini.getIntDefault("llm", "retry_delay_seconds", 5)
+	cfg.LLMTimeoutSeconds = ini.getIntDefault("llm", "timeout_seconds", 600)
+	cfg.LLMRepairAttempts = ini.getIntDefault("llm", "repair_attempts", 2)
+	translateSpec := "ollama"
+	if cfg.SubtitleTranslateModel != "" {
+		translateSpec = "ollama:" + cfg.SubtitleTranslateModel
+	}
+	cfg.ReviewMinAccuracy = ini.getFloatDefault("review", "min_accuracy", 7)
+	cfg.ReviewMaxHallucinationRisk = strings.ToLower(ini.getDefault("review", "max_hallucination_risk", "medium"))
+	cfg.ReviewSlack = ini.getBoolDefault("review", "slack", true)
+
+	cfg.SimilarityCheck = ini.getBoolDefault("similarity", "check", true)
+	cfg.SimilarityThreshold = ini.getFloatDefault("similarity", "threshold", 0.92)
+	cfg.SimilarityRegenerations = ini.getIntDefault("similarity", "regenerations", 2)
+
+	cfg.SubjectStrategy = strings.ToLower(ini.getDefault("subjects", "strategy", "weighted"))
+	cfg.SubjectCooldownHours = ini.getFloatDefault("subjects", "cooldown_hours", 72)
+	cfg.SubjectUsageWeight = ini.getFloatDefault("subjects", "usage_weight", 1)
+	cfg.SubjectFreshnessWeight = ini.getFloatDefault("subjects", "freshness_weight", 1)
+	cfg.SubjectFreshnessDays = ini.getFloatDefault("subjects", "freshness_days", 7)
+	cfg.SubjectPerformanceWeight = ini.getFloatDefault("subjects", "performance_weight", 1)
+
+	cfg.YoutubeAPIKey = ini.get("youtube", "api_key")
+	cfg.YoutubeStatsMaxAgeHours = ini.getFloatDefault("youtube", "stats_max_age_hours", 24)
+	cfg.YoutubeScoreMinAgeDays = ini.getFloatDefault("youtube", "score_min_age_days", 7)
+	cfg.YoutubeUploader = strings.ToLower(ini.getDefault("youtube", "uploader", "script"))
+	cfg.YoutubeClientSecretsFile = ini.get("youtube", "client_secrets_file")
+	cfg.YoutubeTokenFile = ini.get("youtube", "token_file")
+	cfg.YoutubeUploadChunkMB = ini.getIntDefault("youtube", "upload_chunk_mb", 8)
+	cfg.YoutubeUploadRetries = ini.getIntDefault("youtube", "upload_retries", 10)
+
+	cfg.RssMaxFailures = ini.getIntDefault("rss", "max_failures", 5)
+	cfg.RssItemMaxAttempts = ini.getIntDefault("rss", "item_max_attempts", 3)
+
+	cfg.FetchUserAgent = ini.getDefault("fetch", "user_agent", "ai-things-manager/1.0")
+	cfg.FetchConcurrency = ini.getIntDefault("fetch", "concurrency", 4)
+	cfg.FetchDomainDelaySeconds = ini.getFloatDefault("fetch", "domain_delay_seconds", 2)
+	cfg.FetchMaxBytes = ini.getIntDefault("fetch", "max_bytes", 5242880)
+	cfg.FetchTimeoutSeconds = ini.getIntDefault("fetch", "timeout_seconds", 30)
+	cfg.FetchRetries = ini.getIntDefault("fetch", "retries", 2)
+
+	cfg.PromptFolder = ini.get("prompts", "folder")
+	if cfg.PromptFolder == "" && cfg.BaseAppFolder != "" {
+		cfg.PromptFolder = filepath.Join(cfg.BaseAppFolder, "prompts")
+	}
+	cfg.PromptPins = map[string]int{}
+	for _, pin := range splitList(ini.get("prompts", "pins")) {
+		name, version, _ := strings.Cut(pin, ":")
+		if parsed, err := strconv.Atoi(strings.TrimSpace(version)); err == nil && parsed > 0 {
+			cfg.PromptPins[strings.TrimSpace(name)] = parsed
+		}
+	}
+
+	cfg.LLMUseCases = map[string]string{}
+	for useCase, fallback := range map[string]string{
+		"funfact":         "ollama",
+		"subject_funfact": "gemini",
+		"subjects":        "gemini",
+		"image_prompt":    "portnumber53:llama3.3",
+		"chat":            "portnumber53:llama3.1:8b",
+		"translate":       translateSpec,
+		"review":          "gemini",
+		"embed":           "ollama:nomic-embed-text",
+		"wisdom":          "gemini",
+		"article":         "gemini",
+	} {
+		cfg.LLMUseCases[useCase] = ini.getDefault("llm", useCase, fallback)
+	}
 	cfg.TikTokAccessToken = ini.get("tiktok", "access_token")
 	cfg.TikTokVideoPath = ini.get("tiktok", "video_path")
 
@@ -300,6 +522,40 @@ func (ini iniData) getIntDefault(section, key string, fallback int) int {
 	return parsed
 }
 
+func (ini iniData) getFloatDefault(section, key string, fallback float64) float64 {
+	value := ini.get(section, key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func (ini iniData) getBoolDefault(section, key string, fallback bool) bool {
+	value := ini.get(section, key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, value := range values {
 		if value != "" {
@@ -38,9 +38,17 @@ type Subscription struct {
 	SiteURL       *string
 	LastFetchedAt *time.Time
 	LastBuildDate *time.Time
-	IsActive      bool
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	// ETag and LastModified come from the last 200 response and are sent back as conditional GET
+	// headers.
+	ETag         *string
+	LastModified *string
+	// LastError, LastErrorAt and FailureCount track consecutive failed fetches.
+	LastError    *string
+	LastErrorAt  *time.Time
+	FailureCount int
+	IsActive     bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 type Collection struct {
@@ -49,10 +57,22 @@ type Collection struct {
 	Title       string
 	Language    string
 	HTMLContent string
+	// TextContent, Byline and PublishedAt are extracted from HTMLContent (internal/readable).
+	TextContent string
+	Byline      string
+	PublishedAt *time.Time
 	FetchedAt   time.Time
 	ProcessedAt *time.Time
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// ContentID is the content Collection:GenerateContent made from this article.
+	ContentID *int64
+	// FetchStatus (fetcher.Status), FetchError, HTTPStatus and FetchAttempts describe the last
+	// article fetch; failed fetches leave HTMLContent empty.
+	FetchStatus   string
+	FetchError    string
+	HTTPStatus    *int
+	FetchAttempts int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 type Subject struct {
@@ -62,8 +82,35 @@ type Subject struct {
 	IsActive      bool
 	PodcastsCount int
 	LastUsedAt    *time.Time
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	// LastSeenAt is when Subject:ProcessCollections last extracted the subject from an article.
+	LastSeenAt *time.Time
+	// Score is the relative performance of the subject's videos (1 = typical) over ScoreVideos
+	// videos; nil until one has statistics.
+	Score       *float64
+	ScoreVideos int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SubjectVideoStats are the YouTube counters of one content, for the subject score rollup.
+type SubjectVideoStats struct {
+	SubjectID   int64
+	ContentID   int64
+	Views       int64
+	Likes       int64
+	Comments    int64
+	PublishedAt *time.Time
+}
+
+// ContentEmbedding is a content's text vector for one embedding model.
+type ContentEmbedding struct {
+	ContentID  int64
+	Model      string
+	TextSHA256 string
+	Embedding  []float32
+	// Title is filled in by ListContentEmbeddings.
+	Title     string
+	UpdatedAt time.Time
 }
 
 type SlackInstallation struct {
@@ -311,10 +358,10 @@ func (s *Store) QueryContents(ctx context.Context, query string, args ...any) ([
 	return contents, rows.Err()
 }
 
-// FindContentBySlackImageThread finds a content row linked to a Slack image request thread.
-// The linkage is stored in contents.meta.slack_image_request.{team_id,channel_id,thread_ts}
-// and may also be present in contents.meta.slack_image_requests[] (history).
-func (s *Store) FindContentBySlackImageThread(ctx context.Context, teamID, channelID, threadTS string) (Content, error) {
+// FindContentBySlackThread finds a content row linked to a Slack thread. The linkage is stored in
+// contents.meta.<metaKey>.{team_id,channel_id,thread_ts} (e.g. slack_image_request or
+// slack_funfact_review_request) and may also be present in contents.meta.<metaKey>s[] (history).
+func (s *Store) FindContentBySlackThread(ctx context.Context, metaKey, teamID, channelID, threadTS string) (Content, error) {
 	if strings.TrimSpace(teamID) == "" || strings.TrimSpace(channelID) == "" || strings.TrimSpace(threadTS) == "" {
 		return Content{}, errors.New("missing teamID/channelID/threadTS")
 	}
@@ -327,15 +374,15 @@ func (s *Store) FindContentBySlackImageThread(ctx context.Context, teamID, chann
 		SELECT id, title, status, type, sentences, count, meta, archive, created_at, updated_at
 		FROM contents
 		WHERE (
-			(meta->'slack_image_request'->>'team_id' = $1
-			 AND meta->'slack_image_request'->>'channel_id' = $2
-			 AND meta->'slack_image_request'->>'thread_ts' = $3)
+			(meta->$5::text->>'team_id' = $1
+			 AND meta->$5::text->>'channel_id' = $2
+			 AND meta->$5::text->>'thread_ts' = $3)
 			OR
-			(COALESCE(meta->'slack_image_requests', '[]'::jsonb) @> $4::jsonb)
+			(COALESCE(meta->($5::text || 's'), '[]'::jsonb) @> $4::jsonb)
 		)
 		ORDER BY id
 		LIMIT 1
-	`, teamID, channelID, threadTS, string(needle))
+	`, teamID, channelID, threadTS, string(needle), metaKey)
 	var c Content
 	err := row.Scan(
 		&c.ID,
@@ -412,13 +459,99 @@ func (s *Store) FindContentBySlackYouTubeReviewThread(ctx context.Context, teamI
 	return c, nil
 }
 
+func (s *Store) UpsertContentEmbedding(ctx context.Context, e ContentEmbedding) error {
+	utils.Debug("db upsert content embedding", "content_id", e.ContentID, "model", e.Model, "dims", len(e.Embedding))
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO content_embeddings (content_id, model, text_sha256, embedding, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (content_id, model) DO UPDATE
+		SET text_sha256 = EXCLUDED.text_sha256,
+			embedding = EXCLUDED.embedding,
+			updated_at = NOW()
+	`, e.ContentID, e.Model, e.TextSHA256, e.Embedding)
+	return err
+}
+
+func (s *Store) GetContentEmbedding(ctx context.Context, contentID int64, model string) (ContentEmbedding, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT e.content_id, e.model, e.text_sha256, e.embedding, c.title, e.updated_at
+		FROM content_embeddings e
+		JOIN contents c ON c.id = e.content_id
+		WHERE e.content_id = $1 AND e.model = $2
+	`, contentID, model)
+	var e ContentEmbedding
+	if err := row.Scan(&e.ContentID, &e.Model, &e.TextSHA256, &e.Embedding, &e.Title, &e.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ContentEmbedding{}, nil
+		}
+		return ContentEmbedding{}, err
+	}
+	return e, nil
+}
+
+// ListContentEmbeddings returns every embedding stored for a model, with the content title.
+func (s *Store) ListContentEmbeddings(ctx context.Context, model string) ([]ContentEmbedding, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT e.content_id, e.model, e.text_sha256, e.embedding, c.title, e.updated_at
+		FROM content_embeddings e
+		JOIN contents c ON c.id = e.content_id
+		WHERE e.model = $1
+		ORDER BY e.content_id
+	`, model)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ContentEmbedding
+	for rows.Next() {
+		var e ContentEmbedding
+		if err := rows.Scan(&e.ContentID, &e.Model, &e.TextSHA256, &e.Embedding, &e.Title, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func scanSubscription(row pgx.Row) (Subscription, error) {
+	var sub Subscription
+	err := row.Scan(
+		&sub.ID,
+		&sub.FeedURL,
+		&sub.Title,
+		&sub.Description,
+		&sub.SiteURL,
+		&sub.LastFetchedAt,
+		&sub.LastBuildDate,
+		&sub.ETag,
+		&sub.LastModified,
+		&sub.LastError,
+		&sub.LastErrorAt,
+		&sub.FailureCount,
+		&sub.IsActive,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+	)
+	return sub, err
+}
+
 func (s *Store) ListActiveSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return s.listSubscriptions(ctx, true)
+}
+
+// ListSubscriptions returns every subscription, paused ones included.
+func (s *Store) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return s.listSubscriptions(ctx, false)
+}
+
+func (s *Store) listSubscriptions(ctx context.Context, activeOnly bool) ([]Subscription, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT id, feed_url, title, description, site_url, last_fetched_at, last_build_date, is_active, created_at, updated_at
+		SELECT id, feed_url, title, description, site_url, last_fetched_at, last_build_date, etag, last_modified, last_error, last_error_at, failure_count, is_active, created_at, updated_at
 		FROM subscriptions
-		WHERE is_active = true
+		WHERE is_active = true OR NOT $1
 		ORDER BY id
-	`)
+	`, activeOnly)
 	if err != nil {
 		return nil, err
 	}
@@ -426,38 +559,167 @@ func (s *Store) ListActiveSubscriptions(ctx context.Context) ([]Subscription, er
 
 	var subs []Subscription
 	for rows.Next() {
-		var ssub Subscription
-		if err := rows.Scan(
-			&ssub.ID,
-			&ssub.FeedURL,
-			&ssub.Title,
-			&ssub.Description,
-			&ssub.SiteURL,
-			&ssub.LastFetchedAt,
-			&ssub.LastBuildDate,
-			&ssub.IsActive,
-			&ssub.CreatedAt,
-			&ssub.UpdatedAt,
-		); err != nil {
+		sub, err := scanSubscription(rows)
+		if err != nil {
 			return nil, err
 		}
-		subs = append(subs, ssub)
+		subs = append(subs, sub)
 	}
 	return subs, rows.Err()
 }
 
-func (s *Store) InsertSubscription(ctx context.Context, sub Subscription) error {
-	utils.Debug("db insert subscription", "url", sub.FeedURL)
-	_, err := s.pool.Exec(ctx, `
+// GetSubscriptionByID returns a zero Subscription when none matches.
+func (s *Store) GetSubscriptionByID(ctx context.Context, id int64) (Subscription, error) {
+	sub, err := scanSubscription(s.pool.QueryRow(ctx, `
+		SELECT id, feed_url, title, description, site_url, last_fetched_at, last_build_date, etag, last_modified, last_error, last_error_at, failure_count, is_active, created_at, updated_at
+		FROM subscriptions
+		WHERE id = $1
+	`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Subscription{}, nil
+	}
+	return sub, err
+}
+
+// GetSubscriptionByURL returns a zero Subscription when none matches.
+func (s *Store) GetSubscriptionByURL(ctx context.Context, feedURL string) (Subscription, error) {
+	sub, err := scanSubscription(s.pool.QueryRow(ctx, `
+		SELECT id, feed_url, title, description, site_url, last_fetched_at, last_build_date, etag, last_modified, last_error, last_error_at, failure_count, is_active, created_at, updated_at
+		FROM subscriptions
+		WHERE feed_url = $1
+	`, feedURL))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Subscription{}, nil
+	}
+	return sub, err
+}
+
+// UpsertSubscription inserts a feed or, when feed_url exists, updates its title, description,
+// site url and dates (nil fields keep the stored value). is_active is only set on insert; use
+// SetSubscriptionActive to pause or resume. It returns the subscription id.
+func (s *Store) UpsertSubscription(ctx context.Context, sub Subscription) (int64, error) {
+	utils.Debug("db upsert subscription", "url", sub.FeedURL)
+	var id int64
+	err := s.pool.QueryRow(ctx, `
 		INSERT INTO subscriptions (feed_url, title, description, site_url, last_fetched_at, last_build_date, is_active, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
-	`, sub.FeedURL, sub.Title, sub.Description, sub.SiteURL, sub.LastFetchedAt, sub.LastBuildDate, sub.IsActive)
+		ON CONFLICT (feed_url) DO UPDATE
+		SET title = COALESCE(EXCLUDED.title, subscriptions.title),
+			description = COALESCE(EXCLUDED.description, subscriptions.description),
+			site_url = COALESCE(EXCLUDED.site_url, subscriptions.site_url),
+			last_fetched_at = COALESCE(EXCLUDED.last_fetched_at, subscriptions.last_fetched_at),
+			last_build_date = COALESCE(EXCLUDED.last_build_date, subscriptions.last_build_date),
+			updated_at = NOW()
+		RETURNING id
+	`, sub.FeedURL, sub.Title, sub.Description, sub.SiteURL, sub.LastFetchedAt, sub.LastBuildDate, sub.IsActive).Scan(&id)
+	return id, err
+}
+
+// SetSubscriptionActive pauses or resumes a feed. Resuming clears the failure count so a feed
+// deactivated for failing gets a fresh set of attempts.
+func (s *Store) SetSubscriptionActive(ctx context.Context, id int64, active bool) error {
+	utils.Debug("db set subscription active", "id", id, "active", active)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE subscriptions
+		SET is_active = $1,
+			failure_count = CASE WHEN $1 THEN 0 ELSE failure_count END,
+			last_error = CASE WHEN $1 THEN NULL ELSE last_error END,
+			last_error_at = CASE WHEN $1 THEN NULL ELSE last_error_at END,
+			updated_at = NOW()
+		WHERE id = $2
+	`, active, id)
 	return err
 }
 
+// DeleteSubscription removes a feed. Collections fetched from it are kept.
+func (s *Store) DeleteSubscription(ctx context.Context, id int64) error {
+	utils.Debug("db delete subscription", "id", id)
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM subscriptions
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+// RecordSubscriptionFetched stores a successful fetch: the conditional GET validators, the feed's
+// build date, and resets the failure count. A 304 keeps the stored validators (nil leaves a
+// column unchanged).
+func (s *Store) RecordSubscriptionFetched(ctx context.Context, sub Subscription) error {
+	utils.Debug("db record subscription fetched", "id", sub.ID)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE subscriptions
+		SET etag = COALESCE($1, etag),
+			last_modified = COALESCE($2, last_modified),
+			last_build_date = COALESCE($3, last_build_date),
+			last_fetched_at = NOW(),
+			last_error = NULL,
+			last_error_at = NULL,
+			failure_count = 0,
+			updated_at = NOW()
+		WHERE id = $4
+	`, sub.ETag, sub.LastModified, sub.LastBuildDate, sub.ID)
+	return err
+}
+
+// RecordSubscriptionFailure stores a failed fetch and deactivates the subscription once
+// failure_count reaches maxFailures (0 never deactivates). It reports whether it was deactivated.
+func (s *Store) RecordSubscriptionFailure(ctx context.Context, id int64, message string, maxFailures int) (bool, error) {
+	utils.Debug("db record subscription failure", "id", id, "err", message)
+	row := s.pool.QueryRow(ctx, `
+		UPDATE subscriptions
+		SET last_error = $1,
+			last_error_at = NOW(),
+			failure_count = failure_count + 1,
+			is_active = is_active AND NOT ($2 > 0 AND failure_count + 1 >= $2),
+			updated_at = NOW()
+		WHERE id = $3
+		RETURNING is_active
+	`, message, maxFailures, id)
+	var active bool
+	if err := row.Scan(&active); err != nil {
+		return false, err
+	}
+	return !active, nil
+}
+
+func (s *Store) GetCollectionByID(ctx context.Context, id int64) (Collection, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT id, url, title, language, html_content, COALESCE(text_content, ''), COALESCE(byline, ''), published_at, fetched_at, processed_at, content_id, COALESCE(fetch_status, ''), COALESCE(fetch_error, ''), http_status, fetch_attempts, created_at, updated_at
+		FROM collections
+		WHERE id = $1
+	`, id)
+	var c Collection
+	err := row.Scan(
+		&c.ID,
+		&c.URL,
+		&c.Title,
+		&c.Language,
+		&c.HTMLContent,
+		&c.TextContent,
+		&c.Byline,
+		&c.PublishedAt,
+		&c.FetchedAt,
+		&c.ProcessedAt,
+		&c.ContentID,
+		&c.FetchStatus,
+		&c.FetchError,
+		&c.HTTPStatus,
+		&c.FetchAttempts,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Collection{}, nil
+		}
+		return Collection{}, err
+	}
+	return c, nil
+}
+
 func (s *Store) GetCollectionByURL(ctx context.Context, url string) (Collection, error) {
 	row := s.pool.QueryRow(ctx, `
-		SELECT id, url, title, language, html_content, fetched_at, processed_at, created_at, updated_at
+		SELECT id, url, title, language, html_content, COALESCE(text_content, ''), COALESCE(byline, ''), published_at, fetched_at, processed_at, content_id, COALESCE(fetch_status, ''), COALESCE(fetch_error, ''), http_status, fetch_attempts, created_at, updated_at
 		FROM collections
 		WHERE url = $1
 	`, url)
@@ -468,8 +730,16 @@ func (s *Store) GetCollectionByURL(ctx context.Context, url string) (Collection,
 		&c.Title,
 		&c.Language,
 		&c.HTMLContent,
+		&c.TextContent,
+		&c.Byline,
+		&c.PublishedAt,
 		&c.FetchedAt,
 		&c.ProcessedAt,
+		&c.ContentID,
+		&c.FetchStatus,
+		&c.FetchError,
+		&c.HTTPStatus,
+		&c.FetchAttempts,
 		&c.CreatedAt,
 		&c.UpdatedAt,
 	)
@@ -485,21 +755,47 @@ func (s *Store) GetCollectionByURL(ctx context.Context, url string) (Collection,
 func (s *Store) InsertCollection(ctx context.Context, c Collection) error {
 	utils.Debug("db insert collection", "url", c.URL)
 	_, err := s.pool.Exec(ctx, `
-		INSERT INTO collections (url, title, language, html_content, fetched_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
-	`, c.URL, c.Title, c.Language, c.HTMLContent, c.FetchedAt)
+		INSERT INTO collections (url, title, language, html_content, text_content, byline, published_at, fetched_at, fetch_status, http_status, fetch_attempts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'ok', $9, 1, NOW(), NOW())
+	`, c.URL, c.Title, c.Language, c.HTMLContent, c.TextContent, c.Byline, c.PublishedAt, c.FetchedAt, c.HTTPStatus)
 	return err
 }
 
-func (s *Store) UpdateCollectionHTML(ctx context.Context, id int64, html string) error {
-	utils.Debug("db update collection html", "id", id, "html_len", len(html))
+// UpdateCollectionContent stores a refetched article: html, extracted text, byline and date.
+func (s *Store) UpdateCollectionContent(ctx context.Context, c Collection) error {
+	utils.Debug("db update collection content", "id", c.ID, "html_len", len(c.HTMLContent), "text_len", len(c.TextContent))
 	_, err := s.pool.Exec(ctx, `
 		UPDATE collections
 		SET html_content = $1,
+			text_content = $2,
+			byline = $3,
+			published_at = $4,
 			fetched_at = NOW(),
+			fetch_status = 'ok',
+			fetch_error = NULL,
+			http_status = $5,
+			fetch_attempts = fetch_attempts + 1,
 			updated_at = NOW()
-		WHERE id = $2
-	`, html, id)
+		WHERE id = $6
+	`, c.HTMLContent, c.TextContent, c.Byline, c.PublishedAt, c.HTTPStatus, c.ID)
+	return err
+}
+
+// RecordCollectionFetchFailure stores a failed article fetch (c.FetchStatus, c.FetchError,
+// c.HTTPStatus), inserting an empty row for a new url and counting the attempt. Content fetched
+// earlier is kept.
+func (s *Store) RecordCollectionFetchFailure(ctx context.Context, c Collection) error {
+	utils.Debug("db record collection fetch failure", "url", c.URL, "status", c.FetchStatus)
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO collections (url, title, language, html_content, fetched_at, fetch_status, fetch_error, http_status, fetch_attempts, created_at, updated_at)
+		VALUES ($1, $2, $3, '', NOW(), $4, $5, $6, 1, NOW(), NOW())
+		ON CONFLICT (url) DO UPDATE
+		SET fetch_status = EXCLUDED.fetch_status,
+			fetch_error = EXCLUDED.fetch_error,
+			http_status = EXCLUDED.http_status,
+			fetch_attempts = collections.fetch_attempts + 1,
+			updated_at = NOW()
+	`, c.URL, c.Title, c.Language, c.FetchStatus, c.FetchError, c.HTTPStatus)
 	return err
 }
 
@@ -514,11 +810,48 @@ func (s *Store) MarkCollectionProcessed(ctx context.Context, id int64) error {
 	return err
 }
 
+// LinkCollectionContent records the content generated from a collection.
+func (s *Store) LinkCollectionContent(ctx context.Context, id, contentID int64) error {
+	utils.Debug("db link collection content", "id", id, "content_id", contentID)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE collections
+		SET content_id = $1,
+			updated_at = NOW()
+		WHERE id = $2
+	`, contentID, id)
+	return err
+}
+
+// MarkCollectionContentSkipped keeps Collection:GenerateContent from retrying a collection it
+// decided not to turn into content.
+func (s *Store) MarkCollectionContentSkipped(ctx context.Context, id int64) error {
+	utils.Debug("db mark collection content skipped", "id", id)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE collections
+		SET content_skipped_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+// ListCollectionsUnprocessed lists fetched collections Subject:ProcessCollections has not
+// analysed yet.
 func (s *Store) ListCollectionsUnprocessed(ctx context.Context, lastID int64, limit int) ([]Collection, error) {
+	return s.listCollections(ctx, "processed_at IS NULL", lastID, limit)
+}
+
+// ListCollectionsWithoutContent lists fetched collections Collection:GenerateContent has neither
+// generated content for nor skipped.
+func (s *Store) ListCollectionsWithoutContent(ctx context.Context, lastID int64, limit int) ([]Collection, error) {
+	return s.listCollections(ctx, "content_id IS NULL AND content_skipped_at IS NULL", lastID, limit)
+}
+
+func (s *Store) listCollections(ctx context.Context, where string, lastID int64, limit int) ([]Collection, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT id, url, title, language, html_content, fetched_at, processed_at, created_at, updated_at
+		SELECT id, url, title, language, html_content, COALESCE(text_content, ''), COALESCE(byline, ''), published_at, fetched_at, processed_at, content_id, COALESCE(fetch_status, ''), COALESCE(fetch_error, ''), http_status, fetch_attempts, created_at, updated_at
 		FROM collections
-		WHERE processed_at IS NULL AND id > $1
+		WHERE `+where+` AND html_content <> '' AND id > $1
 		ORDER BY id
 		LIMIT $2
 	`, lastID, limit)
@@ -536,8 +869,16 @@ func (s *Store) ListCollectionsUnprocessed(ctx context.Context, lastID int64, li
 			&c.Title,
 			&c.Language,
 			&c.HTMLContent,
+			&c.TextContent,
+			&c.Byline,
+			&c.PublishedAt,
 			&c.FetchedAt,
 			&c.ProcessedAt,
+			&c.ContentID,
+			&c.FetchStatus,
+			&c.FetchError,
+			&c.HTTPStatus,
+			&c.FetchAttempts,
 			&c.CreatedAt,
 			&c.UpdatedAt,
 		); err != nil {
@@ -548,14 +889,48 @@ func (s *Store) ListCollectionsUnprocessed(ctx context.Context, lastID int64, li
 	return collections, rows.Err()
 }
 
-func (s *Store) FindRandomSubject(ctx context.Context) (Subject, error) {
+// ListSubjects returns subjects ordered by id, inactive ones too unless activeOnly.
+func (s *Store) ListSubjects(ctx context.Context, activeOnly bool) ([]Subject, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, subject, keywords, is_active, podcasts_count, last_used_at, last_seen_at, score, score_videos, created_at, updated_at
+		FROM subjects
+		WHERE is_active = true OR NOT $1
+		ORDER BY id
+	`, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Subject
+	for rows.Next() {
+		var subj Subject
+		if err := rows.Scan(
+			&subj.ID,
+			&subj.Subject,
+			&subj.Keywords,
+			&subj.IsActive,
+			&subj.PodcastsCount,
+			&subj.LastUsedAt,
+			&subj.LastSeenAt,
+			&subj.Score,
+			&subj.ScoreVideos,
+			&subj.CreatedAt,
+			&subj.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, subj)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) GetSubjectByID(ctx context.Context, id int64) (Subject, error) {
 	row := s.pool.QueryRow(ctx, `
-		SELECT id, subject, keywords, is_active, podcasts_count, last_used_at, created_at, updated_at
+		SELECT id, subject, keywords, is_active, podcasts_count, last_used_at, last_seen_at, score, score_videos, created_at, updated_at
 		FROM subjects
-		WHERE podcasts_count < 1 AND is_active = true
-		ORDER BY random()
-		LIMIT 1
-	`)
+		WHERE id = $1
+	`, id)
 	var subj Subject
 	if err := row.Scan(
 		&subj.ID,
@@ -564,6 +939,9 @@ func (s *Store) FindRandomSubject(ctx context.Context) (Subject, error) {
 		&subj.IsActive,
 		&subj.PodcastsCount,
 		&subj.LastUsedAt,
+		&subj.LastSeenAt,
+		&subj.Score,
+		&subj.ScoreVideos,
 		&subj.CreatedAt,
 		&subj.UpdatedAt,
 	); err != nil {
@@ -577,7 +955,7 @@ func (s *Store) FindRandomSubject(ctx context.Context) (Subject, error) {
 
 func (s *Store) GetSubjectByName(ctx context.Context, name string) (Subject, error) {
 	row := s.pool.QueryRow(ctx, `
-		SELECT id, subject, keywords, is_active, podcasts_count, last_used_at, created_at, updated_at
+		SELECT id, subject, keywords, is_active, podcasts_count, last_used_at, last_seen_at, score, score_videos, created_at, updated_at
 		FROM subjects
 		WHERE subject = $1
 		LIMIT 1
@@ -590,6 +968,40 @@ func (s *Store) GetSubjectByName(ctx context.Context, name string) (Subject, err
 		&subj.IsActive,
 		&subj.PodcastsCount,
 		&subj.LastUsedAt,
+		&subj.LastSeenAt,
+		&subj.Score,
+		&subj.ScoreVideos,
+		&subj.CreatedAt,
+		&subj.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Subject{}, nil
+		}
+		return Subject{}, err
+	}
+	return subj, nil
+}
+
+// GetSubjectByNameFold is GetSubjectByName ignoring case and surrounding whitespace.
+func (s *Store) GetSubjectByNameFold(ctx context.Context, name string) (Subject, error) {
+	row := s.pool.QueryRow(ctx, `
+		SELECT id, subject, keywords, is_active, podcasts_count, last_used_at, last_seen_at, score, score_videos, created_at, updated_at
+		FROM subjects
+		WHERE lower(trim(subject)) = lower(trim($1))
+		ORDER BY id
+		LIMIT 1
+	`, name)
+	var subj Subject
+	if err := row.Scan(
+		&subj.ID,
+		&subj.Subject,
+		&subj.Keywords,
+		&subj.IsActive,
+		&subj.PodcastsCount,
+		&subj.LastUsedAt,
+		&subj.LastSeenAt,
+		&subj.Score,
+		&subj.ScoreVideos,
 		&subj.CreatedAt,
 		&subj.UpdatedAt,
 	); err != nil {
@@ -601,11 +1013,52 @@ func (s *Store) GetSubjectByName(ctx context.Context, name string) (Subject, err
 	return subj, nil
 }
 
+// FindSubjectsInText returns active subjects whose name appears as whole words in text, longest
+// names first.
+func (s *Store) FindSubjectsInText(ctx context.Context, text string, limit int) ([]Subject, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, subject, keywords, is_active, podcasts_count, last_used_at, last_seen_at, score, score_videos, created_at, updated_at
+		FROM subjects
+		WHERE is_active = true
+		  AND length(trim(subject)) >= 3
+		  AND ' ' || regexp_replace(lower($1), '[^[:alnum:]]+', ' ', 'g') || ' '
+			LIKE '% ' || regexp_replace(lower(trim(subject)), '[^[:alnum:]]+', ' ', 'g') || ' %'
+		ORDER BY length(subject) DESC, podcasts_count DESC
+		LIMIT $2
+	`, text, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Subject
+	for rows.Next() {
+		var subj Subject
+		if err := rows.Scan(
+			&subj.ID,
+			&subj.Subject,
+			&subj.Keywords,
+			&subj.IsActive,
+			&subj.PodcastsCount,
+			&subj.LastUsedAt,
+			&subj.LastSeenAt,
+			&subj.Score,
+			&subj.ScoreVideos,
+			&subj.CreatedAt,
+			&subj.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, subj)
+	}
+	return out, rows.Err()
+}
+
 func (s *Store) InsertSubject(ctx context.Context, name string) error {
 	utils.Debug("db insert subject", "subject_len", len(name))
 	_, err := s.pool.Exec(ctx, `
-		INSERT INTO subjects (subject, is_active, podcasts_count, created_at, updated_at)
-		VALUES ($1, true, 0, NOW(), NOW())
+		INSERT INTO subjects (subject, is_active, podcasts_count, last_seen_at, created_at, updated_at)
+		VALUES ($1, true, 0, NOW(), NOW(), NOW())
 	`, name)
 	return err
 }
@@ -622,6 +1075,139 @@ func (s *Store) IncrementSubjectPodcasts(ctx context.Context, id int64) error {
 	return err
 }
 
+// CountSubjectPodcast counts content classified after the fact. Unlike IncrementSubjectPodcasts
+// it does not mark the subject as used now: last_used_at only moves forward to createdAt, so
+// backfills leave recent usage (and the fun fact cooldown) alone.
+func (s *Store) CountSubjectPodcast(ctx context.Context, id int64, createdAt time.Time) error {
+	utils.Debug("db count subject podcast", "id", id, "created_at", createdAt)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE subjects
+		SET podcasts_count = podcasts_count + 1,
+			last_used_at = GREATEST(COALESCE(last_used_at, $2), $2),
+			updated_at = NOW()
+		WHERE id = $1
+	`, id, createdAt)
+	return err
+}
+
+// DecrementSubjectPodcasts undoes IncrementSubjectPodcasts when content moves to another subject.
+func (s *Store) DecrementSubjectPodcasts(ctx context.Context, id int64) error {
+	utils.Debug("db decrement subject podcasts", "id", id)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE subjects
+		SET podcasts_count = GREATEST(podcasts_count - 1, 0),
+			updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+// MarkSubjectSeen records that an article mentioned the subject again.
+func (s *Store) MarkSubjectSeen(ctx context.Context, id int64) error {
+	utils.Debug("db mark subject seen", "id", id)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE subjects
+		SET last_seen_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+// ListSubjectVideoStats returns the stored YouTube counters of every content assigned to a
+// subject (meta.subject.id) that has them. Counts are matched as digits before casting, since
+// legacy rows may hold them as strings; a non-numeric view_count skips the row.
+func (s *Store) ListSubjectVideoStats(ctx context.Context) ([]SubjectVideoStats, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT (meta->'subject'->>'id')::bigint,
+			id,
+			(meta->>'view_count')::bigint,
+			CASE WHEN meta->>'like_count' ~ '^[0-9]+$' THEN (meta->>'like_count')::bigint ELSE 0 END,
+			CASE WHEN meta->>'comments' ~ '^[0-9]+$' THEN (meta->>'comments')::bigint ELSE 0 END,
+			(meta->'youtube'->>'published_at')::timestamptz
+		FROM contents
+		WHERE meta->'subject'->>'id' ~ '^[0-9]+$'
+		  AND meta->>'view_count' ~ '^[0-9]+$'
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SubjectVideoStats
+	for rows.Next() {
+		var v SubjectVideoStats
+		if err := rows.Scan(&v.SubjectID, &v.ContentID, &v.Views, &v.Likes, &v.Comments, &v.PublishedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// ReplaceSubjectScores stores scores (subject id -> score, videos) and clears the score of every
+// other subject.
+func (s *Store) ReplaceSubjectScores(ctx context.Context, scores map[int64]float64, videos map[int64]int) error {
+	utils.Debug("db replace subject scores", "subjects", len(scores))
+	ids := make([]int64, 0, len(scores))
+	values := make([]float64, 0, len(scores))
+	counts := make([]int32, 0, len(scores))
+	for id, score := range scores {
+		ids = append(ids, id)
+		values = append(values, score)
+		counts = append(counts, int32(videos[id]))
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `
+		UPDATE subjects
+		SET score = NULL,
+			score_videos = 0,
+			score_updated_at = NOW()
+		WHERE score IS NOT NULL AND NOT (id = ANY($1))
+	`, ids); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE subjects AS s
+		SET score = v.score,
+			score_videos = v.videos,
+			score_updated_at = NOW()
+		FROM unnest($1::bigint[], $2::float8[], $3::int[]) AS v(id, score, videos)
+		WHERE s.id = v.id
+	`, ids, values, counts); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *Store) SetSubjectActive(ctx context.Context, id int64, active bool) error {
+	utils.Debug("db set subject active", "id", id, "active", active)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE subjects
+		SET is_active = $1,
+			updated_at = NOW()
+		WHERE id = $2
+	`, active, id)
+	return err
+}
+
+// SetSubjectKeywords replaces the comma-separated keywords (nil clears them).
+func (s *Store) SetSubjectKeywords(ctx context.Context, id int64, keywords *string) error {
+	utils.Debug("db set subject keywords", "id", id)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE subjects
+		SET keywords = $1,
+			updated_at = NOW()
+		WHERE id = $2
+	`, keywords, id)
+	return err
+}
+
 func (s *Store) UpsertSlackInstallation(ctx context.Context, inst SlackInstallation) error {
 	utils.Debug("db upsert slack installation", "team_id", inst.TeamID)
 	_, err := s.pool.Exec(ctx, `
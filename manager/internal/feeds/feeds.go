@@ -0,0 +1,302 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Feed is a parsed RSS, Atom or JSON Feed document.
+type Feed struct {
+	Title       string
+	Description string
+	// Link is the site URL.
+	Link    string
+	Updated *time.Time
+	Items   []Item
+}
+
+// Item is one article link in a feed.
+type Item struct {
+	// ID is the item guid/id, or the URL when the feed has none.
+	ID        string
+	Title     string
+	URL       string
+	Published *time.Time
+}
+
+// Parse detects the format (RSS 0.9x/2.0, RSS 1.0, Atom 1.0, JSON Feed 1.x) and parses it. Google
+// Trends RSS items are expanded into one item per news_item article.
+func Parse(data []byte) (Feed, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\xef\xbb\xbf"))
+	if len(data) == 0 {
+		return Feed{}, errors.New("empty feed")
+	}
+	if data[0] == '{' {
+		return parseJSONFeed(data)
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return Feed{}, err
+	}
+	switch strings.ToLower(root) {
+	case "rss", "rdf":
+		return parseRSS(data)
+	case "feed":
+		return parseAtom(data)
+	default:
+		return Feed{}, fmt.Errorf("unsupported feed root element <%s>", root)
+	}
+}
+
+func rootElement(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = charsetReader
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("not a feed: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func decodeXML(data []byte, v any) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = charsetReader
+	return decoder.Decode(v)
+}
+
+// charsetReader decodes the non-UTF-8 charsets feeds declare (ISO-8859-1, windows-1252, ...)
+// with the WHATWG encoding labels.
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	return charset.NewReaderLabel(label, input)
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title         string    `xml:"title"`
+		Description   string    `xml:"description"`
+		Link          []rssLink `xml:"link"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		PubDate       string    `xml:"pubDate"`
+		Items         []rssItem `xml:"item"`
+	} `xml:"channel"`
+	// RSS 1.0 (RDF) puts items next to the channel.
+	Items []rssItem `xml:"item"`
+}
+
+// rssLink matches both <link>url</link> and <atom:link href="..."/> inside a channel.
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Text string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title     string     `xml:"title"`
+	Link      string     `xml:"link"`
+	GUID      string     `xml:"guid"`
+	PubDate   string     `xml:"pubDate"`
+	Date      string     `xml:"http://purl.org/dc/elements/1.1/ date"`
+	NewsItems []rssTrend `xml:"news_item"`
+}
+
+// rssTrend is a Google Trends ht:news_item. It is matched by local name, so the namespace URL
+// (https://trends.google.com/trending/rss in the live feed) does not have to match exactly.
+type rssTrend struct {
+	Title string `xml:"news_item_title"`
+	URL   string `xml:"news_item_url"`
+}
+
+func parseRSS(data []byte) (Feed, error) {
+	var doc rssDocument
+	if err := decodeXML(data, &doc); err != nil {
+		return Feed{}, fmt.Errorf("rss: %w", err)
+	}
+	feed := Feed{
+		Title:       clean(doc.Channel.Title),
+		Description: clean(doc.Channel.Description),
+		Updated:     ParseTime(firstNonEmpty(doc.Channel.LastBuildDate, doc.Channel.PubDate)),
+	}
+	for _, link := range doc.Channel.Link {
+		if text := strings.TrimSpace(link.Text); text != "" {
+			feed.Link = text
+			break
+		}
+	}
+
+	for _, item := range append(doc.Channel.Items, doc.Items...) {
+		published := ParseTime(firstNonEmpty(item.PubDate, item.Date))
+		if len(item.NewsItems) > 0 {
+			for _, news := range item.NewsItems {
+				feed.Items = append(feed.Items, Item{
+					ID:        strings.TrimSpace(news.URL),
+					Title:     clean(news.Title),
+					URL:       strings.TrimSpace(news.URL),
+					Published: published,
+				})
+			}
+			continue
+		}
+		link := strings.TrimSpace(item.Link)
+		if link == "" && strings.HasPrefix(item.GUID, "http") {
+			link = strings.TrimSpace(item.GUID)
+		}
+		feed.Items = append(feed.Items, Item{
+			ID:        firstNonEmpty(item.GUID, link),
+			Title:     clean(item.Title),
+			URL:       link,
+			Published: published,
+		})
+	}
+	return feed, nil
+}
+
+type atomDocument struct {
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Links     []atomLink `xml:"link"`
+}
+
+func parseAtom(data []byte) (Feed, error) {
+	var doc atomDocument
+	if err := decodeXML(data, &doc); err != nil {
+		return Feed{}, fmt.Errorf("atom: %w", err)
+	}
+	feed := Feed{
+		Title:       clean(doc.Title),
+		Description: clean(doc.Subtitle),
+		Link:        alternateLink(doc.Links),
+		Updated:     ParseTime(doc.Updated),
+	}
+	for _, entry := range doc.Entries {
+		link := alternateLink(entry.Links)
+		feed.Items = append(feed.Items, Item{
+			ID:        firstNonEmpty(entry.ID, link),
+			Title:     clean(entry.Title),
+			URL:       link,
+			Published: ParseTime(firstNonEmpty(entry.Published, entry.Updated)),
+		})
+	}
+	return feed, nil
+}
+
+// alternateLink is the rel="alternate" (or rel-less) link, which points at the HTML page.
+func alternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+type jsonFeed struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	HomePageURL string `json:"home_page_url"`
+	Items       []struct {
+		ID            any    `json:"id"`
+		URL           string `json:"url"`
+		ExternalURL   string `json:"external_url"`
+		Title         string `json:"title"`
+		DatePublished string `json:"date_published"`
+		DateModified  string `json:"date_modified"`
+	} `json:"items"`
+}
+
+func parseJSONFeed(data []byte) (Feed, error) {
+	var doc jsonFeed
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Feed{}, fmt.Errorf("json feed: %w", err)
+	}
+	if !strings.Contains(doc.Version, "jsonfeed.org") {
+		return Feed{}, errors.New("json feed: missing jsonfeed.org version")
+	}
+	feed := Feed{
+		Title:       clean(doc.Title),
+		Description: clean(doc.Description),
+		Link:        strings.TrimSpace(doc.HomePageURL),
+	}
+	for _, item := range doc.Items {
+		link := strings.TrimSpace(firstNonEmpty(item.URL, item.ExternalURL))
+		feed.Items = append(feed.Items, Item{
+			// JSON Feed ids are strings, but some generators emit numbers.
+			ID:        firstNonEmpty(fmt.Sprint(item.ID), link),
+			Title:     clean(item.Title),
+			URL:       link,
+			Published: ParseTime(firstNonEmpty(item.DatePublished, item.DateModified)),
+		})
+	}
+	return feed, nil
+}
+
+var timeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// ParseTime parses the date formats feeds use (RFC 822/1123 for RSS, RFC 3339 for Atom and JSON
+// Feed); it returns nil for empty or unknown values.
+func ParseTime(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" && v != "<nil>" {
+			return v
+		}
+	}
+	return ""
+}
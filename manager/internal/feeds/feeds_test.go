@@ -0,0 +1,210 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLegacyCharsets(t *testing.T) {
+	tests := []struct {
+		file      string
+		title     string
+		itemTitle string
+		itemURL   string
+	}{
+		{"latin1.xml", "Café Crème", "L'été à Montréal", "https://example.com/ete-a-montreal"},
+		{"windows1252.xml", "“Quoted” News", "Price – €5", "https://example.org/price"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			feed, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if feed.Title != tt.title {
+				t.Errorf("title = %q, want %q", feed.Title, tt.title)
+			}
+			if len(feed.Items) != 1 {
+				t.Fatalf("items = %d, want 1", len(feed.Items))
+			}
+			if got := feed.Items[0]; got.Title != tt.itemTitle || got.URL != tt.itemURL {
+				t.Errorf("item = %q %q, want %q %q", got.Title, got.URL, tt.itemTitle, tt.itemURL)
+			}
+		})
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		file  string
+		title string
+		desc  string
+		link  string
+		// updated and published are RFC 3339 in UTC, or empty for none.
+		updated string
+		items   []string
+	}{
+		{
+			file:    "rss2.xml",
+			title:   "Science Daily",
+			desc:    "News about électrons & stars",
+			link:    "https://example.com/",
+			updated: "2026-10-19T09:00:00Z",
+			items: []string{
+				"comet-2026 | Comet returns | https://example.com/comet | 2026-10-18T22:15:00Z",
+				// A permalink guid stands in for the missing <link>; dc:date for pubDate.
+				"https://example.com/ice-on-mars | Ice <em>on</em> Mars | https://example.com/ice-on-mars | 2026-10-17T10:00:00Z",
+			},
+		},
+		{
+			file:  "rdf.xml",
+			title: "Old School",
+			desc:  "An RSS 1.0 feed",
+			link:  "https://example.net/",
+			items: []string{
+				"https://example.net/one | First | https://example.net/one | 2026-10-10T10:00:00Z",
+				"https://example.net/two | Second | https://example.net/two | ",
+			},
+		},
+		{
+			file:    "atom.xml",
+			title:   "Atom Blog",
+			desc:    "Notes",
+			link:    "https://blog.example.org/",
+			updated: "2026-10-19T07:00:00Z",
+			items: []string{
+				"tag:blog.example.org,2026:1 | Tides & moons | https://blog.example.org/tides | 2026-10-15T08:00:00Z",
+				"https://blog.example.org/no-id | Untitled id | https://blog.example.org/no-id | 2026-10-14T13:00:00Z",
+			},
+		},
+		{
+			file:  "feed.json",
+			title: "JSON Notes",
+			desc:  "A JSON Feed",
+			link:  "https://json.example.com/",
+			items: []string{
+				"a1 | Alpha | https://json.example.com/a1 | 2026-10-18T12:00:00Z",
+				"42 | Beta | https://elsewhere.example.com/b | 2026-10-17T12:00:00Z",
+				"https://json.example.com/c | Gamma | https://json.example.com/c | ",
+			},
+		},
+		{
+			file:  "google_trends.xml",
+			title: "Daily Search Trends",
+			link:  "https://trends.google.com/trending/rss?geo=US",
+			// Each trend expands into its news articles.
+			items: []string{
+				"https://news.example.com/aurora-texas | Northern lights seen as far south as Texas | https://news.example.com/aurora-texas | 2026-10-19T11:00:00Z",
+				"https://news.example.com/aurora-photos | How to photograph the aurora | https://news.example.com/aurora-photos | 2026-10-19T11:00:00Z",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			feed, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if feed.Title != tt.title || feed.Description != tt.desc || feed.Link != tt.link || utc(feed.Updated) != tt.updated {
+				t.Errorf("feed = %q %q %q %q, want %q %q %q %q", feed.Title, feed.Description, feed.Link, utc(feed.Updated), tt.title, tt.desc, tt.link, tt.updated)
+			}
+			var items []string
+			for _, item := range feed.Items {
+				items = append(items, strings.Join([]string{item.ID, item.Title, item.URL, utc(item.Published)}, " | "))
+			}
+			if got, want := strings.Join(items, "\n"), strings.Join(tt.items, "\n"); got != want {
+				t.Errorf("items:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func utc(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := map[string]string{
+		"empty":                "  \n",
+		"html page":            "<!DOCTYPE html><html><body>Not a feed</body></html>",
+		"json without version": `{"title":"x","items":[]}`,
+		"not xml":              "hello",
+	}
+	for name, input := range tests {
+		if _, err := Parse([]byte(input)); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestFetchConditional(t *testing.T) {
+	var requests []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Clone())
+		switch r.URL.Path {
+		case "/feed":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", ` "v1" `)
+			w.Header().Set("Last-Modified", "Mon, 19 Oct 2026 09:00:00 GMT")
+			fmt.Fprint(w, "<rss/>")
+		case "/big":
+			w.Write(bytes.Repeat([]byte("x"), maxFeedSize+1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	resp, err := Fetch(ctx, srv.Client(), srv.URL+"/feed", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.NotModified || string(resp.Body) != "<rss/>" || resp.ETag != `"v1"` || resp.LastModified != "Mon, 19 Oct 2026 09:00:00 GMT" {
+		t.Errorf("first fetch = %+v", resp)
+	}
+	if requests[0].Get("If-None-Match") != "" || requests[0].Get("If-Modified-Since") != "" {
+		t.Errorf("first fetch sent validators: %v", requests[0])
+	}
+
+	resp, err = Fetch(ctx, srv.Client(), srv.URL+"/feed", resp.ETag, resp.LastModified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A 304 keeps the stored validators.
+	if !resp.NotModified || len(resp.Body) != 0 || resp.ETag != `"v1"` || resp.LastModified != "Mon, 19 Oct 2026 09:00:00 GMT" {
+		t.Errorf("conditional fetch = %+v", resp)
+	}
+	if requests[1].Get("If-Modified-Since") != "Mon, 19 Oct 2026 09:00:00 GMT" {
+		t.Errorf("If-Modified-Since = %q", requests[1].Get("If-Modified-Since"))
+	}
+
+	if _, err := Fetch(ctx, srv.Client(), srv.URL+"/big", "", ""); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("oversized feed: err = %v", err)
+	}
+	if _, err := Fetch(ctx, srv.Client(), srv.URL+"/missing", "", ""); err == nil || !strings.Contains(err.Error(), "status=404") {
+		t.Errorf("missing feed: err = %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxFeedSize caps the feed body read into memory.
+const maxFeedSize = 10 << 20
+
+// Response is the result of a conditional feed fetch.
+type Response struct {
+	// NotModified is true on a 304; Body is empty and the stored validators stay valid.
+	NotModified  bool
+	Body         []byte
+	ETag         string
+	LastModified string
+}
+
+// Fetch GETs a feed, sending If-None-Match/If-Modified-Since when etag/lastModified are set.
+// Non-2xx answers (other than 304) are errors.
+func Fetch(ctx context.Context, client *http.Client, feedURL, etag, lastModified string) (Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return Response{}, err
+	}
+	req.Header.Set("User-Agent", "ai-things-manager/1.0 (+feed reader)")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, text/xml;q=0.9, */*;q=0.8")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return Response{NotModified: true, ETag: etag, LastModified: lastModified}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Response{}, fmt.Errorf("status=%d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return Response{}, err
+	}
+	if len(body) > maxFeedSize {
+		return Response{}, fmt.Errorf("feed larger than %d bytes", maxFeedSize)
+	}
+	return Response{
+		Body:         body,
+		ETag:         strings.TrimSpace(resp.Header.Get("ETag")),
+		LastModified: strings.TrimSpace(resp.Header.Get("Last-Modified")),
+	}, nil
+}
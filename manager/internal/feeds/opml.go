@@ -0,0 +1,88 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Outline is one feed in an OPML subscription list.
+type Outline struct {
+	Title   string
+	FeedURL string
+	SiteURL string
+}
+
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    struct {
+		Title       string `xml:"title"`
+		DateCreated string `xml:"dateCreated,omitempty"`
+	} `xml:"head"`
+	Body struct {
+		Outlines []opmlOutline `xml:"outline"`
+	} `xml:"body"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ParseOPML returns the feeds (outlines with an xmlUrl) of an OPML document, folders flattened.
+func ParseOPML(data []byte) ([]Outline, error) {
+	var doc opmlDocument
+	if err := decodeXML(data, &doc); err != nil {
+		return nil, fmt.Errorf("opml: %w", err)
+	}
+	var outlines []Outline
+	var walk func(items []opmlOutline)
+	walk = func(items []opmlOutline) {
+		for _, item := range items {
+			if feedURL := strings.TrimSpace(item.XMLURL); feedURL != "" {
+				outlines = append(outlines, Outline{
+					Title:   clean(firstNonEmpty(item.Title, item.Text)),
+					FeedURL: feedURL,
+					SiteURL: strings.TrimSpace(item.HTMLURL),
+				})
+			}
+			walk(item.Outlines)
+		}
+	}
+	walk(doc.Body.Outlines)
+	return outlines, nil
+}
+
+// WriteOPML writes an OPML 2.0 document listing the outlines, without folders.
+func WriteOPML(w io.Writer, title string, outlines []Outline) error {
+	var doc opmlDocument
+	doc.Version = "2.0"
+	doc.Head.Title = title
+	doc.Head.DateCreated = time.Now().UTC().Format(time.RFC1123Z)
+	for _, o := range outlines {
+		doc.Body.Outlines = append(doc.Body.Outlines, opmlOutline{
+			Text:    firstNonEmpty(o.Title, o.FeedURL),
+			Title:   o.Title,
+			Type:    "rss",
+			XMLURL:  o.FeedURL,
+			HTMLURL: o.SiteURL,
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
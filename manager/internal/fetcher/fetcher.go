@@ -0,0 +1,394 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html/charset"
+
+	"ai-things/manager-go/internal/utils"
+)
+
+var (
+	// ErrDisallowed is returned for URLs robots.txt does not allow.
+	ErrDisallowed = errors.New("disallowed by robots.txt")
+	// ErrTooLarge is returned when the body is larger than Options.MaxBytes.
+	ErrTooLarge = errors.New("response too large")
+	// ErrContentType is returned when the Content-Type is not in Options.ContentTypes.
+	ErrContentType = errors.New("unsupported content type")
+)
+
+// StatusError is a non-2xx response.
+type StatusError struct {
+	Code int
+	// RetryAfter is the Retry-After delay of a 429/503, if any.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status=%d", e.Code)
+}
+
+// Options configures a Fetcher; zero values get the defaults noted on each field.
+type Options struct {
+	// UserAgent is sent with every request and matched against robots.txt groups by its product
+	// token (the part before "/").
+	UserAgent string
+	// Concurrency caps simultaneous requests across all hosts (default 4).
+	Concurrency int
+	// DomainDelay is the minimum gap between requests to the same host (default 1s). A larger
+	// robots.txt Crawl-delay wins, up to maxCrawlDelay.
+	DomainDelay time.Duration
+	// MaxBytes caps the response body (default 5 MiB).
+	MaxBytes int64
+	// Timeout applies to each request (default 30s).
+	Timeout time.Duration
+	// Retries is how often network errors, 408, 429 and 5xx are retried with backoff.
+	Retries int
+	// ContentTypes lists accepted media types; empty accepts text/html and application/xhtml+xml.
+	ContentTypes []string
+	// Client overrides the HTTP client (its Timeout is left alone).
+	Client *http.Client
+}
+
+const maxCrawlDelay = time.Minute
+
+// Page is a fetched document.
+type Page struct {
+	// URL is the final URL after redirects.
+	URL         string
+	StatusCode  int
+	ContentType string
+	// Charset is the detected source encoding; Body is always UTF-8.
+	Charset string
+	Body    string
+}
+
+// Fetcher downloads pages politely: bounded concurrency, a delay between requests to the same
+// host, robots.txt rules, a body size cap and content-type checks. It is safe for concurrent use.
+type Fetcher struct {
+	opts   Options
+	client *http.Client
+	slots  chan struct{}
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+// host is the per-host state: the next free request time and the cached robots.txt.
+type host struct {
+	mu   sync.Mutex
+	next time.Time
+
+	robotsOnce sync.Once
+	robots     *robots
+	robotsErr  error
+}
+
+// New returns a Fetcher.
+func New(opts Options) *Fetcher {
+	if opts.UserAgent == "" {
+		opts.UserAgent = "ai-things-manager/1.0"
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.DomainDelay <= 0 {
+		opts.DomainDelay = time.Second
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = 5 << 20
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if len(opts.ContentTypes) == 0 {
+		opts.ContentTypes = []string{"text/html", "application/xhtml+xml"}
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+	return &Fetcher{
+		opts:   opts,
+		client: client,
+		slots:  make(chan struct{}, opts.Concurrency),
+		hosts:  map[string]*host{},
+	}
+}
+
+// Concurrency is the configured number of simultaneous requests.
+func (f *Fetcher) Concurrency() int {
+	return f.opts.Concurrency
+}
+
+// Fetch downloads rawURL, honoring robots.txt and the host delay, and retries transient failures.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Page{}, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return Page{}, fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+	h := f.host(u.Host)
+
+	rules, err := f.robotsFor(ctx, u, h)
+	if err != nil {
+		return Page{}, err
+	}
+	if !rules.allowed(u) {
+		return Page{}, ErrDisallowed
+	}
+	delay := max(f.opts.DomainDelay, min(rules.crawlDelay, maxCrawlDelay))
+
+	for attempt := 0; ; attempt++ {
+		page, err := f.get(ctx, h, delay, u.String(), f.opts.ContentTypes, f.opts.MaxBytes)
+		if err == nil || attempt >= f.opts.Retries || !Retryable(err) || ctx.Err() != nil {
+			return page, err
+		}
+		wait := time.Duration(1<<attempt) * 2 * time.Second
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > wait {
+			wait = min(statusErr.RetryAfter, maxCrawlDelay)
+		}
+		utils.Debug("fetch retry", "url", rawURL, "attempt", attempt+1, "wait", wait, "err", err)
+		if err := sleep(ctx, wait); err != nil {
+			return Page{}, err
+		}
+	}
+}
+
+// FetchAll fetches urls with Concurrency workers and calls handle for each result, from the
+// worker goroutines. The first error handle returns stops the run and is returned.
+func (f *Fetcher) FetchAll(ctx context.Context, urls []string, handle func(rawURL string, page Page, err error) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < min(f.opts.Concurrency, len(urls)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rawURL := range queue {
+				page, err := f.Fetch(ctx, rawURL)
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := handle(rawURL, page, err); err != nil {
+					cancel(err)
+				}
+			}
+		}()
+	}
+	for _, rawURL := range urls {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- rawURL
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (f *Fetcher) host(name string) *host {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	h, ok := f.hosts[name]
+	if !ok {
+		h = &host{}
+		f.hosts[name] = h
+	}
+	return h
+}
+
+// robotsFor loads robots.txt once per host. A missing file (4xx) allows everything; a server
+// error or network failure is returned so the page is retried on a later run.
+func (f *Fetcher) robotsFor(ctx context.Context, u *url.URL, h *host) (*robots, error) {
+	h.robotsOnce.Do(func() {
+		robotsURL := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}).String()
+		page, err := f.get(ctx, h, f.opts.DomainDelay, robotsURL, []string{"text/plain"}, 512<<10)
+		var statusErr *StatusError
+		switch {
+		case err == nil:
+			h.robots = parseRobots(page.Body, f.opts.UserAgent)
+		case errors.As(err, &statusErr) && statusErr.Code >= 400 && statusErr.Code < 500,
+			errors.Is(err, ErrContentType), errors.Is(err, ErrTooLarge):
+			h.robots = &robots{}
+		default:
+			h.robotsErr = fmt.Errorf("robots.txt: %w", err)
+		}
+	})
+	return h.robots, h.robotsErr
+}
+
+// reserve waits until the host may be contacted again and books the next slot delay later.
+func (f *Fetcher) reserve(ctx context.Context, h *host, delay time.Duration) error {
+	h.mu.Lock()
+	now := time.Now()
+	start := h.next
+	if start.Before(now) {
+		start = now
+	}
+	h.next = start.Add(delay)
+	h.mu.Unlock()
+	return sleep(ctx, start.Sub(now))
+}
+
+func (f *Fetcher) get(ctx context.Context, h *host, delay time.Duration, rawURL string, contentTypes []string, maxBytes int64) (Page, error) {
+	if err := f.reserve(ctx, h, delay); err != nil {
+		return Page{}, err
+	}
+	select {
+	case f.slots <- struct{}{}:
+		defer func() { <-f.slots }()
+	case <-ctx.Done():
+		return Page{}, ctx.Err()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Page{}, err
+	}
+	req.Header.Set("User-Agent", f.opts.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.5")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Page{}, err
+	}
+	defer resp.Body.Close()
+
+	page := Page{URL: resp.Request.URL.String(), StatusCode: resp.StatusCode}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return page, &StatusError{Code: resp.StatusCode, RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	}
+
+	page.ContentType = resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(page.ContentType)
+	if page.ContentType != "" && !accepted(mediaType, contentTypes) {
+		return page, fmt.Errorf("%w: %s", ErrContentType, mediaType)
+	}
+	if resp.ContentLength > maxBytes {
+		return page, ErrTooLarge
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return page, err
+	}
+	if int64(len(body)) > maxBytes {
+		return page, ErrTooLarge
+	}
+	if page.ContentType == "" {
+		page.ContentType = http.DetectContentType(body)
+		mediaType, _, _ = mime.ParseMediaType(page.ContentType)
+		if !accepted(mediaType, contentTypes) {
+			return page, fmt.Errorf("%w: %s", ErrContentType, mediaType)
+		}
+	}
+
+	// Content-Type charset, BOM, <meta charset>, then UTF-8 validity (else windows-1252).
+	encoding, name, _ := charset.DetermineEncoding(body, page.ContentType)
+	page.Charset = name
+	if name != "utf-8" {
+		decoded, err := encoding.NewDecoder().Bytes(body)
+		if err != nil {
+			return page, fmt.Errorf("decode %s: %w", name, err)
+		}
+		body = decoded
+	}
+	page.Body = string(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")))
+	return page, nil
+}
+
+func accepted(mediaType string, contentTypes []string) bool {
+	for _, t := range contentTypes {
+		if strings.EqualFold(mediaType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// Retryable reports transient failures: network errors, 408, 429 and 5xx.
+func Retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code == http.StatusRequestTimeout || statusErr.Code == http.StatusTooManyRequests || statusErr.Code >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Permanent reports failures that will not go away on a later run: robots.txt, size, content
+// type, and 4xx answers other than 408/429.
+func Permanent(err error) bool {
+	if errors.Is(err, ErrDisallowed) || errors.Is(err, ErrTooLarge) || errors.Is(err, ErrContentType) {
+		return true
+	}
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code >= 400 && statusErr.Code < 500 && !Retryable(err)
+}
+
+// Status is the short fetch status stored with a page: ok, disallowed, too_large, content_type,
+// http_error or error.
+func Status(err error) string {
+	var statusErr *StatusError
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, ErrDisallowed):
+		return "disallowed"
+	case errors.Is(err, ErrTooLarge):
+		return "too_large"
+	case errors.Is(err, ErrContentType):
+		return "content_type"
+	case errors.As(err, &statusErr):
+		return "http_error"
+	default:
+		return "error"
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,217 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// site is a test server that records when each path was requested.
+type site struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	requests map[string][]time.Time
+	agents   []string
+}
+
+func newSite(t *testing.T, robots string, handler http.HandlerFunc) *site {
+	s := &site{requests: map[string][]time.Time{}}
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.URL.Path] = append(s.requests[r.URL.Path], time.Now())
+		s.agents = append(s.agents, r.UserAgent())
+		s.mu.Unlock()
+		if r.URL.Path == "/robots.txt" {
+			if robots == "" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, robots)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func (s *site) times(path string) []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Time(nil), s.requests[path]...)
+}
+
+func htmlPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, "<html><body><p>%s</p></body></html>", r.URL.Path)
+}
+
+func TestFetchRobots(t *testing.T) {
+	s := newSite(t, "User-agent: *\nDisallow: /private\n", htmlPage)
+	f := New(Options{UserAgent: "ai-things-manager/1.0", DomainDelay: time.Millisecond, Client: s.srv.Client()})
+	ctx := context.Background()
+
+	page, err := f.Fetch(ctx, s.srv.URL+"/public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.StatusCode != http.StatusOK || page.Charset != "utf-8" || !strings.Contains(page.Body, "<p>/public</p>") {
+		t.Errorf("page = %+v", page)
+	}
+	if _, err := f.Fetch(ctx, s.srv.URL+"/private/1"); !errors.Is(err, ErrDisallowed) {
+		t.Errorf("err = %v, want ErrDisallowed", err)
+	}
+	if _, err := f.Fetch(ctx, s.srv.URL+"/public/2"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(s.times("/robots.txt")); n != 1 {
+		t.Errorf("robots.txt fetched %d times, want once per host", n)
+	}
+	if len(s.times("/private/1")) != 0 {
+		t.Error("a disallowed page was requested")
+	}
+	for _, agent := range s.agents {
+		if agent != "ai-things-manager/1.0" {
+			t.Errorf("User-Agent = %q", agent)
+		}
+	}
+}
+
+func TestFetchRobotsErrors(t *testing.T) {
+	// A missing robots.txt allows everything.
+	s := newSite(t, "", htmlPage)
+	f := New(Options{DomainDelay: time.Millisecond, Client: s.srv.Client()})
+	if _, err := f.Fetch(context.Background(), s.srv.URL+"/page"); err != nil {
+		t.Errorf("without robots.txt: %v", err)
+	}
+
+	// A failing robots.txt is an error, so the page is retried on a later run.
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	f = New(Options{DomainDelay: time.Millisecond, Client: broken.Client()})
+	_, err := f.Fetch(context.Background(), broken.URL+"/page")
+	if err == nil || !strings.Contains(err.Error(), "robots.txt") || Permanent(err) {
+		t.Errorf("err = %v, want a transient robots.txt error", err)
+	}
+}
+
+func TestFetchHostDelay(t *testing.T) {
+	const delay = 50 * time.Millisecond
+	s := newSite(t, "", htmlPage)
+	f := New(Options{DomainDelay: delay, Concurrency: 4, Client: s.srv.Client()})
+	urls := []string{s.srv.URL + "/a", s.srv.URL + "/a", s.srv.URL + "/a"}
+	err := f.FetchAll(context.Background(), urls, func(rawURL string, page Page, err error) error {
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Concurrent workers still take turns on the host, robots.txt included.
+	times := append(s.times("/robots.txt"), s.times("/a")...)
+	if len(times) != 4 {
+		t.Fatalf("requests = %d, want 4", len(times))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < delay-5*time.Millisecond {
+			t.Errorf("gap %d = %v, want at least %v", i, gap, delay)
+		}
+	}
+}
+
+func TestFetchCrawlDelay(t *testing.T) {
+	s := newSite(t, "User-agent: *\nCrawl-delay: 0.1\n", htmlPage)
+	f := New(Options{DomainDelay: time.Millisecond, Client: s.srv.Client()})
+	for i := 0; i < 2; i++ {
+		if _, err := f.Fetch(context.Background(), s.srv.URL+"/page"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	times := s.times("/page")
+	if gap := times[1].Sub(times[0]); gap < 95*time.Millisecond {
+		t.Errorf("gap = %v, want the 100ms Crawl-delay", gap)
+	}
+}
+
+func TestFetchRejects(t *testing.T) {
+	big := strings.Repeat("x", 2048)
+	s := newSite(t, "", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			fmt.Fprint(w, "%PDF-1.4")
+		case "/declared":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Length", "2048")
+			fmt.Fprint(w, big)
+		case "/chunked":
+			// Flushing first sends the body without a Content-Length.
+			w.Header().Set("Content-Type", "text/html")
+			w.(http.Flusher).Flush()
+			fmt.Fprint(w, big)
+		case "/sniffed":
+			w.Header()["Content-Type"] = nil
+			fmt.Fprint(w, "\x89PNG\r\n\x1a\n")
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/busy":
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	f := New(Options{DomainDelay: time.Millisecond, MaxBytes: 1024, Client: s.srv.Client()})
+
+	tests := []struct {
+		path      string
+		status    string
+		permanent bool
+		retryable bool
+	}{
+		{"/pdf", "content_type", true, false},
+		{"/declared", "too_large", true, false},
+		{"/chunked", "too_large", true, false},
+		{"/sniffed", "content_type", true, false},
+		{"/gone", "http_error", true, false},
+		{"/busy", "http_error", false, true},
+	}
+	for _, tt := range tests {
+		_, err := f.Fetch(context.Background(), s.srv.URL+tt.path)
+		if Status(err) != tt.status || Permanent(err) != tt.permanent || Retryable(err) != tt.retryable {
+			t.Errorf("%s: err = %v (status %s, permanent %v, retryable %v)", tt.path, err, Status(err), Permanent(err), Retryable(err))
+		}
+	}
+	_, err := f.Fetch(context.Background(), s.srv.URL+"/busy")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusServiceUnavailable || statusErr.RetryAfter != 7*time.Second {
+		t.Errorf("busy: err = %#v", err)
+	}
+	if _, err := f.Fetch(context.Background(), "ftp://example.com/file"); err == nil {
+		t.Error("want an error for a non-http url")
+	}
+}
+
+func TestFetchDecodesCharset(t *testing.T) {
+	s := newSite(t, "", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=windows-1252")
+		fmt.Fprint(w, "<p>caf\xe9 \x93quoted\x94</p>")
+	})
+	f := New(Options{DomainDelay: time.Millisecond, Client: s.srv.Client()})
+	page, err := f.Fetch(context.Background(), s.srv.URL+"/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Charset != "windows-1252" || page.Body != "<p>café “quoted”</p>" {
+		t.Errorf("page = %q (%s)", page.Body, page.Charset)
+	}
+}
@@ -0,0 +1,135 @@
+package fetcher
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robots holds the robots.txt rules (RFC 9309) of the group that applies to our user agent.
+type robots struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// parseRobots keeps the group naming our product token, else the "*" group.
+func parseRobots(body, userAgent string) *robots {
+	token := strings.ToLower(strings.TrimSpace(strings.SplitN(userAgent, "/", 2)[0]))
+
+	var groups []*robotsGroup
+	var current *robotsGroup
+	inAgents := false
+	for _, line := range strings.Split(body, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+				inAgents = true
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil || value == "" {
+				continue
+			}
+			if pattern := robotsPattern(value); pattern != nil {
+				current.rules = append(current.rules, robotsRule{allow: key == "allow", length: len(value), pattern: pattern})
+			}
+		case "crawl-delay":
+			inAgents = false
+			if current == nil {
+				continue
+			}
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	var matched, wildcard []*robotsGroup
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			switch {
+			case agent == "*":
+				wildcard = append(wildcard, group)
+			case token != "" && agent == token:
+				matched = append(matched, group)
+			}
+		}
+	}
+	if len(matched) == 0 {
+		matched = wildcard
+	}
+	// Groups for the same agent are merged.
+	result := &robots{}
+	for _, group := range matched {
+		result.rules = append(result.rules, group.rules...)
+		result.crawlDelay = max(result.crawlDelay, group.crawlDelay)
+	}
+	return result
+}
+
+// robotsPattern turns a path pattern ("*" wildcard, "$" end anchor) into a prefix regexp.
+func robotsPattern(value string) *regexp.Regexp {
+	anchored := strings.HasSuffix(value, "$")
+	value = strings.TrimSuffix(value, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil
+	}
+	return pattern
+}
+
+// allowed applies the longest matching rule; on a tie allow wins. No rule means allowed.
+func (r *robots) allowed(u *url.URL) bool {
+	if r == nil {
+		return true
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	allow, length := true, -1
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > length || (rule.length == length && rule.allow) {
+			allow, length = rule.allow, rule.length
+		}
+	}
+	return allow
+}
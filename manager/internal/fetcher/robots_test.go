@@ -0,0 +1,78 @@
+package fetcher
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+const robotsTxt = `# Example robots.txt
+User-agent: *
+Disallow: /
+
+User-agent: OtherBot
+User-agent: ai-things-manager   # our group
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Disallow: /search?q=
+Allow: /tie
+Disallow: /tie
+Crawl-delay: 2.5
+
+User-agent: AI-Things-Manager
+Disallow: /drafts/
+`
+
+func TestParseRobots(t *testing.T) {
+	rules := parseRobots(robotsTxt, "ai-things-manager/1.0 (+https://example.com)")
+	if rules.crawlDelay != 2500*time.Millisecond {
+		t.Errorf("crawl delay = %v, want 2.5s", rules.crawlDelay)
+	}
+	tests := map[string]bool{
+		"/":                      true,
+		"/articles/1":            true,
+		"/private":               false,
+		"/private/notes":         false,
+		"/private/public/page":   true, // the longer Allow beats the Disallow
+		"/paper.pdf":             false,
+		"/paper.pdf?download=1":  true, // $ anchors at the end of path and query
+		"/search?q=cats":         false,
+		"/search?page=2":         true,
+		"/tie":                   true,  // on equal length Allow wins
+		"/drafts/new":            false, // groups for the same agent are merged, case-insensitively
+		"/Private":               true,  // paths are case-sensitive
+		"/private%20space/x.txt": false,
+	}
+	for path, want := range tests {
+		u, err := url.Parse("https://example.com" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rules.allowed(u); got != want {
+			t.Errorf("allowed(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestParseRobotsFallsBackToWildcard(t *testing.T) {
+	rules := parseRobots(robotsTxt, "SomeoneElse/2.0")
+	for path, want := range map[string]bool{"/": false, "/articles/1": false} {
+		u, _ := url.Parse("https://example.com" + path)
+		if got := rules.allowed(u); got != want {
+			t.Errorf("allowed(%s) = %v, want %v", path, got, want)
+		}
+	}
+
+	// No group for us and no wildcard, an empty Disallow, or no robots.txt: everything is allowed.
+	for _, body := range []string{"User-agent: OtherBot\nDisallow: /\n", "User-agent: *\nDisallow:\n", ""} {
+		u, _ := url.Parse("https://example.com/page")
+		if !parseRobots(body, "ai-things-manager/1.0").allowed(u) {
+			t.Errorf("robots %q disallows /page", body)
+		}
+	}
+	var none *robots
+	if !none.allowed(&url.URL{Path: "/"}) {
+		t.Error("nil robots must allow everything")
+	}
+}
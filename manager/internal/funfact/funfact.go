@@ -0,0 +1,167 @@
+package funfact
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MinParagraphs  = 3
+	MaxTitleLength = 120
+)
+
+// FunFact is the JSON document the fun-fact prompts ask for.
+type FunFact struct {
+	Title string `json:"title"`
+	// Hook is a one-sentence teaser (video descriptions, Slack previews); it is not narrated.
+	Hook       string   `json:"hook"`
+	Paragraphs []string `json:"paragraphs"`
+	Sources    []string `json:"sources"`
+	Keywords   []string `json:"keywords"`
+}
+
+// Parse decodes a model answer. It tolerates a preamble and markdown code fences around the JSON
+// object, but not invalid JSON; the caller repairs that by asking the model again.
+func Parse(text string) (FunFact, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return FunFact{}, errors.New("no JSON object in response")
+	}
+	var fact FunFact
+	if err := json.Unmarshal([]byte(text[start:end+1]), &fact); err != nil {
+		return FunFact{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	fact.normalize()
+	return fact, nil
+}
+
+// normalize trims fields and strips markdown emphasis so TTS never reads it out.
+func (f *FunFact) normalize() {
+	clean := func(s string) string {
+		s = strings.ReplaceAll(s, "*", "")
+		s = strings.TrimLeft(strings.TrimSpace(s), "# ")
+		return strings.Join(strings.Fields(s), " ")
+	}
+	f.Title = clean(f.Title)
+	f.Hook = clean(f.Hook)
+	paragraphs := f.Paragraphs[:0]
+	for _, p := range f.Paragraphs {
+		if p = clean(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	f.Paragraphs = paragraphs
+	f.Sources = cleanList(f.Sources)
+	f.Keywords = cleanList(f.Keywords)
+}
+
+func cleanList(items []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[strings.ToLower(item)] {
+			continue
+		}
+		seen[strings.ToLower(item)] = true
+		out = append(out, item)
+	}
+	return out
+}
+
+// Validate checks the document against the schema the prompt describes.
+func (f FunFact) Validate() error {
+	var errs []error
+	if f.Title == "" {
+		errs = append(errs, errors.New("title is empty"))
+	} else if utf8.RuneCountInString(f.Title) > MaxTitleLength {
+		errs = append(errs, fmt.Errorf("title is longer than %d characters", MaxTitleLength))
+	}
+	if len(f.Paragraphs) < MinParagraphs {
+		errs = append(errs, fmt.Errorf("paragraphs has %d entries, want at least %d", len(f.Paragraphs), MinParagraphs))
+	}
+	for i, p := range f.Paragraphs {
+		if strings.HasPrefix(p, "TITLE:") || strings.HasPrefix(p, "CONTENT:") {
+			errs = append(errs, fmt.Errorf("paragraph %d contains a TITLE:/CONTENT: label", i+1))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Text is the narration: one paragraph per line, separated by blank lines (meta.original_text).
+func (f FunFact) Text() string {
+	return strings.Join(f.Paragraphs, "\n\n")
+}
+
+// Meta is stored as meta.funfact.
+func (f FunFact) Meta() map[string]any {
+	return map[string]any{
+		"title":      f.Title,
+		"hook":       f.Hook,
+		"paragraphs": f.Paragraphs,
+		"sources":    f.Sources,
+		"keywords":   f.Keywords,
+	}
+}
+
+// punctuationSpacers is the pause (in TTS spacer units) after a clause ending in this character.
+var punctuationSpacers = map[rune]int{
+	'.': 3,
+	'!': 3,
+	'?': 3,
+	';': 2,
+	',': 1,
+}
+
+// Sentences builds the contents.sentences rows for the TTS: each clause followed by a
+// "<spacer N>" pause sized by its punctuation, and a "<spacer 3>" after every paragraph.
+// It returns the rows and the last count.
+func Sentences(paragraphs []string) ([]map[string]any, int) {
+	rows := []map[string]any{}
+	count := 0
+	add := func(content string) {
+		count++
+		rows = append(rows, map[string]any{
+			"count":   count,
+			"content": content,
+		})
+	}
+	for _, paragraph := range paragraphs {
+		for _, clause := range splitClauses(paragraph, ".!?;,") {
+			last, _ := utf8.DecodeLastRuneInString(clause)
+			spacer := punctuationSpacers[last]
+			if spacer == 0 {
+				spacer = 2
+			}
+			add(clause)
+			add(fmt.Sprintf("<spacer %d>", spacer))
+		}
+		add("<spacer 3>")
+	}
+	return rows, count
+}
+
+// splitClauses splits after any punctuation rune that is followed by whitespace.
+func splitClauses(text, punctuation string) []string {
+	var clauses []string
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !strings.ContainsRune(punctuation, runes[i]) || i+1 >= len(runes) || !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		if clause := strings.TrimSpace(string(runes[start : i+1])); clause != "" {
+			clauses = append(clauses, clause)
+		}
+		start = i + 1
+	}
+	if clause := strings.TrimSpace(string(runes[start:])); clause != "" {
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
@@ -0,0 +1,32 @@
+package funfact
+
+import (
+	"context"
+
+	"ai-things/manager-go/internal/llm"
+	"ai-things/manager-go/internal/prompts"
+)
+
+// Generate asks the provider for a fun fact, repairing invalid answers (see llm.GenerateJSON).
+// req carries the sampling options (temperature, seed); the prompt fills System/User.
+func Generate(ctx context.Context, provider llm.Provider, prompt prompts.Prompt, req llm.Request, repairs int) (FunFact, llm.Response, error) {
+	req.System = prompt.System
+	req.User = prompt.User
+
+	var fact FunFact
+	response, err := llm.GenerateJSON(ctx, provider, req, repairs, func(text string) error {
+		parsed, err := Parse(text)
+		if err != nil {
+			return err
+		}
+		if err := parsed.Validate(); err != nil {
+			return err
+		}
+		fact = parsed
+		return nil
+	})
+	if err != nil {
+		return FunFact{}, llm.Response{}, err
+	}
+	return fact, response, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"strings"
 	"time"
 
 	"ai-things/manager-go/internal/db"
@@ -59,7 +58,7 @@ func (j CorrectSubtitlesJob) Run(ctx context.Context, jctx JobContext, opts JobO
 
 func (j CorrectSubtitlesJob) countWaiting(ctx context.Context, jctx JobContext) (int, error) {
 	where := "WHERE " + db.StatusTrueCondition([]string{"srt_generated"})
-	notFixed := db.StatusNotTrueCondition([]string{"srt_fixed"})
+	notFixed := db.StatusNotTrueCondition([]string{"srt_fixed", "srt_flagged"})
 	if notFixed != "" {
 		where += " AND " + notFixed
 	}
@@ -68,7 +67,7 @@ func (j CorrectSubtitlesJob) countWaiting(ctx context.Context, jctx JobContext)
 
 func (j CorrectSubtitlesJob) selectNext(ctx context.Context, jctx JobContext) (db.Content, error) {
 	where := "WHERE " + db.StatusTrueCondition([]string{"srt_generated"})
-	notFixed := db.StatusNotTrueCondition([]string{"srt_fixed"})
+	notFixed := db.StatusNotTrueCondition([]string{"srt_fixed", "srt_flagged"})
 	if notFixed != "" {
 		where += " AND " + notFixed
 	}
@@ -106,33 +105,51 @@ func (j CorrectSubtitlesJob) processContent(ctx context.Context, jctx JobContext
 	if err != nil {
 		return err
 	}
-	origWords := strings.Fields(originalText)
 
-	captions := subtitles.ParseSRT(srt)
+	captions, err := subtitles.ParseSRT(srt)
+	if err != nil {
+		if len(captions) == 0 {
+			return err
+		}
+		utils.Warn("CorrectSubtitles skipped unparseable caption blocks", "content_id", content.ID, "err", err)
+	}
 	if len(captions) == 0 {
 		return errors.New("no captions parsed")
 	}
 
-	wordIndex := 0
-	for i := range captions {
-		words := strings.Fields(strings.ReplaceAll(captions[i].Text, "\n", " "))
-		for j := range words {
-			if wordIndex >= len(origWords) {
-				break
-			}
-			words[j] = origWords[wordIndex]
-			wordIndex++
-		}
-		captions[i].Text = strings.Join(words, " ")
+	aligned, result := subtitles.AlignCaptions(captions, originalText)
+	alignment := map[string]any{
+		"score":             result.Score,
+		"matched":           result.Matched,
+		"original_tokens":   result.OriginalTokens,
+		"transcript_tokens": result.TranscriptTokens,
+		"threshold":         jctx.Config.SubtitlesMinAlignmentScore,
+		"aligned_at":        time.Now().Format(time.RFC3339),
 	}
+	subtitlesMeta["alignment"] = alignment
+	meta["subtitles"] = subtitlesMeta
 
-	if wordIndex < len(origWords) {
-		captions[len(captions)-1].Text = strings.TrimSpace(captions[len(captions)-1].Text + " " + strings.Join(origWords[wordIndex:], " "))
-	}
+	if result.Score < jctx.Config.SubtitlesMinAlignmentScore || len(aligned) == 0 {
+		// The transcript barely resembles the script (wrong audio, hallucinated whisper output...).
+		// Flag it instead of shipping mistimed captions; Content:Reset or a new transcript clears it.
+		utils.Warn("CorrectSubtitles alignment below threshold; flagging transcript",
+			"content_id", content.ID,
+			"score", result.Score,
+			"threshold", jctx.Config.SubtitlesMinAlignmentScore,
+		)
+		alignment["flagged"] = true
+		utils.SetStatus(meta, "srt_flagged", true)
+		return jctx.Store.UpdateContentMetaStatus(ctx, content.ID, "srt_generated", meta)
+	}
+	utils.Debug("CorrectSubtitles aligned", "content_id", content.ID, "score", result.Score, "captions", len(aligned))
+	captions = aligned
 
 	fixed := subtitles.SerializeSRT(captions)
 	subtitlesMeta["srt"] = fixed
+	// The aligned captions are not laid out for any style; GeneratePodcast reflows them.
+	delete(subtitlesMeta, "style")
 	meta["subtitles"] = subtitlesMeta
+	utils.SetStatus(meta, "srt_flagged", false)
 	utils.SetStatus(meta, j.QueueOutput, true)
 
 	if err := jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta); err != nil {
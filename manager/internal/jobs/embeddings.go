@@ -0,0 +1,151 @@
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/llm"
+	"ai-things/manager-go/internal/utils"
+)
+
+// embedBatchSize is how many texts go into one embed request when backfilling.
+const embedBatchSize = 16
+
+// SimilarContent is a stored content close to a query embedding.
+type SimilarContent struct {
+	ContentID  int64
+	Title      string
+	Similarity float64
+}
+
+// EmbeddingText is the text embedded for a content: its title and narration.
+func EmbeddingText(title, text string) string {
+	return strings.TrimSpace(strings.TrimSpace(title) + "\n\n" + strings.TrimSpace(text))
+}
+
+func contentEmbeddingText(content db.Content) (string, error) {
+	meta, err := utils.DecodeMeta(content.Meta)
+	if err != nil {
+		return "", err
+	}
+	text, err := utils.ExtractTextFromMeta(meta)
+	if err != nil {
+		return "", err
+	}
+	return EmbeddingText(content.Title, text), nil
+}
+
+func textSHA256(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// EmbedContent returns a content's embedding, computing and storing it when it is missing or
+// the text changed since it was stored.
+func EmbedContent(ctx context.Context, jctx JobContext, embedder llm.Embedder, content db.Content) ([]float32, error) {
+	text, err := contentEmbeddingText(content)
+	if err != nil {
+		return nil, err
+	}
+	sum := textSHA256(text)
+	stored, err := jctx.Store.GetContentEmbedding(ctx, content.ID, embedder.Model())
+	if err != nil {
+		return nil, err
+	}
+	if stored.ContentID != 0 && stored.TextSHA256 == sum {
+		return stored.Embedding, nil
+	}
+	vectors, err := embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], StoreEmbedding(ctx, jctx, embedder, content.ID, text, vectors[0])
+}
+
+// StoreEmbedding saves the embedding of text for a content.
+func StoreEmbedding(ctx context.Context, jctx JobContext, embedder llm.Embedder, contentID int64, text string, vector []float32) error {
+	return jctx.Store.UpsertContentEmbedding(ctx, db.ContentEmbedding{
+		ContentID:  contentID,
+		Model:      embedder.Model(),
+		TextSHA256: textSHA256(text),
+		Embedding:  vector,
+	})
+}
+
+// EmbedMissingContents embeds pipeline content that has no embedding for the embedder's model
+// yet (at most limit rows, 0 for all). It returns how many were embedded; contents without
+// narration text are skipped.
+func EmbedMissingContents(ctx context.Context, jctx JobContext, embedder llm.Embedder, limit int) (int, error) {
+	query := `
+		SELECT id, title, status, type, sentences, count, meta, archive, created_at, updated_at
+		FROM contents
+		WHERE type = 'gemini.payload'
+		  AND NOT EXISTS (
+			SELECT 1 FROM content_embeddings e
+			WHERE e.content_id = contents.id AND e.model = $1
+		  )
+		ORDER BY id
+	`
+	args := []any{embedder.Model()}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+	contents, err := jctx.Store.QueryContents(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	embedded := 0
+	for start := 0; start < len(contents); start += embedBatchSize {
+		var ids []int64
+		var texts []string
+		for _, content := range contents[start:min(start+embedBatchSize, len(contents))] {
+			text, err := contentEmbeddingText(content)
+			if err != nil {
+				utils.Debug("embed skip content", "content_id", content.ID, "err", err)
+				continue
+			}
+			ids = append(ids, content.ID)
+			texts = append(texts, text)
+		}
+		if len(texts) == 0 {
+			continue
+		}
+		vectors, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			return embedded, err
+		}
+		for i, id := range ids {
+			if err := StoreEmbedding(ctx, jctx, embedder, id, texts[i], vectors[i]); err != nil {
+				return embedded, err
+			}
+			embedded++
+		}
+		utils.Info("embedded contents", "done", embedded, "total", len(contents))
+	}
+	return embedded, nil
+}
+
+// RankSimilar returns the stored embeddings at least threshold similar to vector, most similar
+// first, skipping excludeID. limit 0 returns all matches.
+func RankSimilar(stored []db.ContentEmbedding, vector []float32, excludeID int64, threshold float64, limit int) []SimilarContent {
+	var out []SimilarContent
+	for _, e := range stored {
+		if e.ContentID == excludeID {
+			continue
+		}
+		if similarity := llm.Cosine(vector, e.Embedding); similarity >= threshold {
+			out = append(out, SimilarContent{ContentID: e.ContentID, Title: e.Title, Similarity: similarity})
+		}
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].Similarity > out[k].Similarity })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"time"
+
+	"ai-things/manager-go/internal/config"
+	"ai-things/manager-go/internal/fetcher"
+)
+
+// NewFetcher returns the article fetcher configured in [fetch].
+func NewFetcher(cfg config.Config) *fetcher.Fetcher {
+	return fetcher.New(fetcher.Options{
+		UserAgent:   cfg.FetchUserAgent,
+		Concurrency: cfg.FetchConcurrency,
+		DomainDelay: time.Duration(cfg.FetchDomainDelaySeconds * float64(time.Second)),
+		MaxBytes:    int64(cfg.FetchMaxBytes),
+		Timeout:     time.Duration(cfg.FetchTimeoutSeconds) * time.Second,
+		Retries:     cfg.FetchRetries,
+	})
+}
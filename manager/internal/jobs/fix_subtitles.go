@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"strings"
 	"time"
 
 	"ai-things/manager-go/internal/db"
@@ -59,7 +58,7 @@ func (j FixSubtitlesJob) Run(ctx context.Context, jctx JobContext, opts JobOptio
 
 func (j FixSubtitlesJob) countWaiting(ctx context.Context, jctx JobContext) (int, error) {
 	where := "WHERE " + db.StatusTrueCondition([]string{"srt_generated"})
-	notFixed := db.StatusNotTrueCondition([]string{"srt_fixed"})
+	notFixed := db.StatusNotTrueCondition([]string{"srt_fixed", "srt_flagged"})
 	if notFixed != "" {
 		where += " AND " + notFixed
 	}
@@ -68,7 +67,7 @@ func (j FixSubtitlesJob) countWaiting(ctx context.Context, jctx JobContext) (int
 
 func (j FixSubtitlesJob) selectNext(ctx context.Context, jctx JobContext) (db.Content, error) {
 	where := "WHERE " + db.StatusTrueCondition([]string{"srt_generated"})
-	notFixed := db.StatusNotTrueCondition([]string{"srt_fixed"})
+	notFixed := db.StatusNotTrueCondition([]string{"srt_fixed", "srt_flagged"})
 	if notFixed != "" {
 		where += " AND " + notFixed
 	}
@@ -102,14 +101,18 @@ func (j FixSubtitlesJob) processContent(ctx context.Context, jctx JobContext, co
 		return errors.New("srt content missing")
 	}
 
-	captions := subtitles.ParseSRT(srt)
-	for i := range captions {
-		text := strings.ReplaceAll(captions[i].Text, "\n", " ")
-		captions[i].Text = strings.Join(strings.Fields(text), " ")
+	captions, err := subtitles.ParseSRT(srt)
+	if err != nil {
+		if len(captions) == 0 {
+			return err
+		}
+		utils.Warn("FixSubtitles skipped unparseable caption blocks", "content_id", content.ID, "err", err)
 	}
-	fixed := subtitles.SerializeSRT(captions)
+	style := subtitleStyle(jctx.Config)
+	fixed := subtitles.SerializeSRT(subtitles.Reflow(captions, style))
 
 	subtitlesMeta["srt"] = fixed
+	subtitlesMeta["style"] = style.Name
 	meta["subtitles"] = subtitlesMeta
 	utils.SetStatus(meta, j.QueueOutput, true)
 
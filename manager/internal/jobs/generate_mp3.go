@@ -239,6 +239,7 @@ func (j GenerateMp3Job) processContent(ctx context.Context, jctx JobContext, con
 			"mp3":         outputFile,
 			"sentence_id": int(sentenceID),
 			"duration":    duration,
+			"bytes":       info.Size(),
 			"hostname":    jctx.Config.Hostname,
 			"sha256":      mp3SHA,
 		},
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/subtitles"
 	"ai-things/manager-go/internal/utils"
 )
 
@@ -299,24 +300,50 @@ func (j GeneratePodcastJob) processContent(ctx context.Context, jctx JobContext,
 		}
 	}
 
+	duration := 0.0
+	if rawDuration, ok := mp3Data["duration"].(float64); ok {
+		duration = rawDuration
+	}
+
+	audioPath, err := mixMusicBed(jctx, content, meta, mp3Path, duration)
+	if err != nil {
+		return err
+	}
+
 	podcastPublic := filepath.Join(jctx.Config.BaseAppFolder, "podcast", "public")
-	if err := utils.CopyFile(mp3Path, filepath.Join(podcastPublic, "audio.mp3")); err != nil {
+	if err := utils.CopyFile(audioPath, filepath.Join(podcastPublic, "audio.mp3")); err != nil {
 		return err
 	}
 	if err := utils.CopyFile(imagePath, filepath.Join(podcastPublic, "image.jpg")); err != nil {
 		return err
 	}
 
-	subtitles, ok := meta["subtitles"].(map[string]any)
+	subtitlesMeta, ok := meta["subtitles"].(map[string]any)
 	if !ok {
 		return errors.New("subtitles missing")
 	}
-	srt, _ := subtitles["srt"].(string)
+	srt, _ := subtitlesMeta["srt"].(string)
 	if srt == "" {
 		utils.Warn("GeneratePodcast srt missing; resetting srt_generated", "content_id", contentID)
 		_ = resetSrtStatus(ctx, jctx, content.ID, meta)
 		return nil
 	}
+	// FixSubtitles already laid the srt out for the style it records in subtitles.style. Captions
+	// from CorrectSubtitles or alignment mode (or laid out for another style) are re-segmented here
+	// so the render always follows the configured profile.
+	style := subtitleStyle(jctx.Config)
+	if laidOut, _ := subtitlesMeta["style"].(string); laidOut != style.Name {
+		captions, err := subtitles.ParseSRT(srt)
+		if err != nil {
+			utils.Warn("GeneratePodcast srt has unparseable blocks", "content_id", contentID, "err", err)
+		}
+		if len(captions) > 0 {
+			srt = subtitles.SerializeSRT(subtitles.Reflow(captions, style))
+			// Keep the srt and its style together so a re-run (and the upload) see the layout.
+			subtitlesMeta["srt"] = srt
+			subtitlesMeta["style"] = style.Name
+		}
+	}
 	if err := os.WriteFile(filepath.Join(podcastPublic, "podcast.srt"), []byte(srt), 0o644); err != nil {
 		return err
 	}
@@ -328,17 +355,12 @@ func (j GeneratePodcastJob) processContent(ctx context.Context, jctx JobContext,
 		return err
 	}
 
-	duration := 0
-	if rawDuration, ok := mp3Data["duration"].(float64); ok {
-		duration = int(rawDuration)
-	}
-
 	replacements := map[string]string{
 		"__REPLACE_WITH_TITLE__":     utils.EscapeJSSingleQuotedString(fmt.Sprintf("%07d - %s", content.ID, content.Title)),
 		"__REPLACE_WITH_MP3__":       utils.EscapeJSSingleQuotedString("audio.mp3"),
 		"__REPLACE_WITH_IMAGE__":     utils.EscapeJSSingleQuotedString("image.jpg"),
 		"__REPLACE_WITH_SUBTITLES__": utils.EscapeJSSingleQuotedString("podcast.srt"),
-		"__DURATION__":               strconv.Itoa(duration),
+		"__DURATION__":               strconv.Itoa(int(duration)),
 	}
 
 	generated := string(templateData)
@@ -2,13 +2,16 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 
+	"ai-things/manager-go/internal/config"
 	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/subtitles"
+	"ai-things/manager-go/internal/transcribe"
 	"ai-things/manager-go/internal/utils"
 )
 
@@ -106,6 +109,51 @@ func (j GenerateSrtJob) selectNext(ctx context.Context, jctx JobContext) (db.Con
 	return content, nil
 }
 
+// newTranscriber builds the configured transcription engine.
+func newTranscriber(cfg config.Config) (transcribe.Engine, error) {
+	return transcribe.New(transcribe.Options{
+		Engine:        cfg.TranscribeEngine,
+		ScriptCommand: cfg.SubtitleScript,
+		ScriptOutDir:  cfg.SubtitleFolder,
+		// Policy: each host must be able to generate subtitles locally (no SSH fallback).
+		ScriptHint: fmt.Sprintf(
+			"Fix: ensure the configured subtitle python on host=%s has deps installed (torch, transformers, datasets, accelerate). "+
+				"If you use the repo podcast venv, run: cd %s/podcast && uv pip install -r requirements.txt",
+			cfg.Hostname,
+			cfg.BaseAppFolder,
+		),
+		WhisperCppBinary: cfg.TranscribeWhisperCppBinary,
+		WhisperCppModel:  cfg.TranscribeWhisperCppModel,
+		HTTPURL:          cfg.TranscribeHTTPURL,
+		HTTPModel:        cfg.TranscribeHTTPModel,
+		HTTPAPIKey:       cfg.TranscribeHTTPAPIKey,
+		HTTPTimeout:      time.Duration(cfg.TranscribeHTTPTimeout) * time.Second,
+		Language:         cfg.TranscribeLanguage,
+	})
+}
+
+// ttsSegments reads the sentence offsets in meta.wav.segments (seconds). GenerateWav detects them
+// from the silences between sentences; wavs where that fails, or voiced before, have none.
+func ttsSegments(wavMeta map[string]any) []transcribe.Segment {
+	raw, _ := wavMeta["segments"].([]any)
+	segments := make([]transcribe.Segment, 0, len(raw))
+	for _, item := range raw {
+		m, _ := item.(map[string]any)
+		text, _ := m["text"].(string)
+		start, okStart := m["start"].(float64)
+		end, okEnd := m["end"].(float64)
+		if text == "" || !okStart || !okEnd {
+			continue
+		}
+		segments = append(segments, transcribe.Segment{
+			Text:  text,
+			Start: time.Duration(start * float64(time.Second)),
+			End:   time.Duration(end * float64(time.Second)),
+		})
+	}
+	return segments
+}
+
 func (j GenerateSrtJob) processContent(ctx context.Context, jctx JobContext, contentID int64) error {
 	utils.Info("GenerateSrt process", "content_id", contentID)
 	content, err := jctx.Store.GetContentByID(ctx, contentID)
@@ -185,32 +233,65 @@ func (j GenerateSrtJob) processContent(ctx context.Context, jctx JobContext, con
 		return fmt.Errorf("wav file missing (expected %s)", wavPath)
 	}
 
-	cmd := fmt.Sprintf("%s %s %d", jctx.Config.SubtitleScript, utils.ShellEscape(wavPath), content.ID)
-	if _, err := utils.RunCommand(cmd); err != nil {
-		// Policy: each host must be able to generate subtitles locally (no SSH fallback).
-		// If this fails, fix the local python environment used by subtitle_script on THIS host.
-		return fmt.Errorf(
-			"subtitle generation failed on host=%s (subtitle_script=%q): %w\n\n"+
-				"Fix: ensure the configured subtitle python has deps installed (torch, transformers, datasets, accelerate). "+
-				"If you use the repo podcast venv, run: cd %s/podcast && uv pip install -r requirements.txt",
-			jctx.Config.Hostname,
-			jctx.Config.SubtitleScript,
-			err,
-			jctx.Config.BaseAppFolder,
-		)
+	// In alignment mode the narration is our own TTS output: caption the known script using the
+	// TTS sentence timings instead of running speech recognition, and skip the correction stages.
+	alignment := jctx.Config.SubtitlesMode == "alignment"
+	var engine transcribe.Engine
+	if alignment {
+		engine = transcribe.TTSEngine{}
+	} else if engine, err = newTranscriber(jctx.Config); err != nil {
+		return err
+	}
+	req := transcribe.Request{
+		ContentID: content.ID,
+		AudioPath: wavPath,
+		Segments:  ttsSegments(wavMeta),
+		LeadIn:    ttsLeadIn,
+		TailPad:   ttsTailPad,
+		Gap:       ttsSentenceSilence,
+	}
+	// Only the tts engine reads the script, and only estimates timings from the wav duration
+	// when there are no recorded segments; speech recognition engines need neither.
+	if _, tts := engine.(transcribe.TTSEngine); tts && len(req.Segments) == 0 {
+		if req.Text, err = utils.ExtractTextFromMeta(meta); err != nil {
+			return err
+		}
+		seconds, err := probeDuration(wavPath)
+		if err != nil {
+			return err
+		}
+		req.Duration = time.Duration(seconds * float64(time.Second))
 	}
 
-	srtPath := filepath.Join(jctx.Config.SubtitleFolder, fmt.Sprintf("transcription_%d.srt", content.ID))
-	data, err := os.ReadFile(srtPath)
+	captions, err := engine.Transcribe(ctx, req)
 	if err != nil {
-		return err
+		return fmt.Errorf("transcription failed on host=%s (engine=%s): %w", jctx.Config.Hostname, engine.Name(), err)
 	}
+	utils.Debug("GenerateSrt transcribed", "content_id", content.ID, "engine", engine.Name(), "captions", len(captions))
 
-	subtitles := map[string]any{
-		"srt": string(data),
+	subtitlesMeta := map[string]any{
+		"srt":    subtitles.SerializeSRT(captions),
+		"engine": engine.Name(),
 	}
-	meta["subtitles"] = subtitles
+	meta["subtitles"] = subtitlesMeta
+	// A fresh transcript gets a fresh chance at alignment in CorrectSubtitles.
+	utils.SetStatus(meta, "srt_flagged", false)
 	utils.SetStatus(meta, j.QueueOutput, true)
 
-	return jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta)
+	if !alignment {
+		return jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta)
+	}
+
+	// The captions already are the script text, so there is nothing for Fix/CorrectSubtitles to do.
+	if len(req.Segments) > 0 {
+		subtitlesMeta["timing"] = "tts_segments"
+	} else {
+		subtitlesMeta["timing"] = "estimated"
+	}
+	utils.SetStatus(meta, "srt_fixed", true)
+	if err := jctx.Store.UpdateContentMetaStatus(ctx, content.ID, "srt_fixed", meta); err != nil {
+		return err
+	}
+	payload, _ := json.Marshal(QueuePayload{ContentID: content.ID, Hostname: jctx.Config.Hostname})
+	return jctx.Queue.Publish("srt_fixed", payload)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +14,14 @@ import (
 	"ai-things/manager-go/internal/utils"
 )
 
+// Silences GenerateWav adds around and between the spoken sentences. Transcription engines that
+// derive timings from the script (transcribe.TTSEngine) need the same values.
+const (
+	ttsLeadIn          = 2 * time.Second
+	ttsTailPad         = 5 * time.Second
+	ttsSentenceSilence = 700 * time.Millisecond
+)
+
 type GenerateWavJob struct {
 	BaseJob
 	MaxWaiting int
@@ -21,7 +30,7 @@ type GenerateWavJob struct {
 func NewGenerateWavJob() GenerateWavJob {
 	return GenerateWavJob{
 		BaseJob: BaseJob{
-			QueueInput:      "funfact_created",
+			QueueInput:      "funfact_reviewed",
 			QueueOutput:     "wav_generated",
 			IgnoreHostCheck: true,
 		},
@@ -29,6 +38,10 @@ func NewGenerateWavJob() GenerateWavJob {
 	}
 }
 
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
 func (j GenerateWavJob) Run(ctx context.Context, jctx JobContext, opts JobOptions) error {
 	if opts.Queue {
 		return j.RunQueue(ctx, jctx, opts, func(ctx context.Context, contentID int64, hostname string) error {
@@ -82,7 +95,7 @@ func (j GenerateWavJob) countWaiting(ctx context.Context, jctx JobContext) (int,
 
 func (j GenerateWavJob) selectNext(ctx context.Context, jctx JobContext) (db.Content, error) {
 	where := "WHERE type = 'gemini.payload'"
-	trueFlags := db.StatusTrueCondition([]string{"funfact_created"})
+	trueFlags := db.StatusTrueCondition([]string{"funfact_created", "funfact_reviewed"})
 	falseFlags := db.StatusNotTrueCondition([]string{"wav_generated"})
 	notUploaded := db.StatusNotTrueCondition([]string{"youtube_uploaded"})
 	missingVideoID := db.MetaKeyMissingCondition([]string{"video_id.v1"})
@@ -162,16 +175,17 @@ func (j GenerateWavJob) processContent(ctx context.Context, jctx JobContext, con
 	}
 
 	cmd := fmt.Sprintf(
-		"echo %s | %s --debug --sentence-silence 0.7 --model %s -c %s --output_file %s && sox %s %s pad %d %d && rm %s",
+		"echo %s | %s --debug --sentence-silence %s --model %s -c %s --output_file %s && sox %s %s pad %s %s && rm %s",
 		utils.ShellEscape(text),
 		utils.ShellEscape(piperPath),
+		formatSeconds(ttsSentenceSilence),
 		utils.ShellEscape(jctx.Config.TTSOnnxModel),
 		utils.ShellEscape(jctx.Config.TTSConfig),
 		utils.ShellEscape(preFile),
 		utils.ShellEscape(preFile),
 		utils.ShellEscape(outputFile),
-		2,
-		5,
+		formatSeconds(ttsLeadIn),
+		formatSeconds(ttsTailPad),
 		utils.ShellEscape(preFile),
 	)
 	_, err = utils.RunCommand(cmd)
@@ -192,12 +206,21 @@ func (j GenerateWavJob) processContent(ctx context.Context, jctx JobContext, con
 		return err
 	}
 
-	meta["wav"] = map[string]any{
+	wavMeta := map[string]any{
 		"filename":    filename,
 		"sentence_id": 0,
 		"hostname":    jctx.Config.Hostname,
 		"sha256":      sha256sum,
 	}
+	// Per-sentence offsets let GenerateSrt time captions without speech recognition.
+	segments, err := detectSentenceSegments(outputFile, text)
+	if err != nil {
+		utils.Warn("GenerateWav segment detection failed", "content_id", content.ID, "err", err)
+	}
+	if len(segments) > 0 {
+		wavMeta["segments"] = segments
+	}
+	meta["wav"] = wavMeta
 	utils.SetStatus(meta, j.QueueOutput, true)
 
 	return jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta)
@@ -0,0 +1,158 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/llm"
+	"ai-things/manager-go/internal/prompts"
+	"ai-things/manager-go/internal/utils"
+)
+
+const (
+	// subjectChoicesLimit caps the existing subjects offered to the model.
+	subjectChoicesLimit = 30
+	maxSubjectLength    = 80
+)
+
+// SubjectMatch is the outcome of IdentifySubject.
+type SubjectMatch struct {
+	Subject    db.Subject
+	Confidence float64
+	// Created is true when no existing subject matched and a new row was inserted.
+	Created bool
+	// Assigned is false when the confidence was below the minimum; the content is left unchanged
+	// apart from meta.subject_identification.
+	Assigned bool
+}
+
+type subjectAnswer struct {
+	Subject    string   `json:"subject"`
+	Confidence *float64 `json:"confidence"`
+}
+
+func (a *subjectAnswer) decode(text string) error {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return errors.New("no JSON object in response")
+	}
+	var parsed subjectAnswer
+	if err := json.Unmarshal([]byte(text[start:end+1]), &parsed); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	parsed.Subject = strings.ToLower(strings.Join(strings.Fields(parsed.Subject), " "))
+	if parsed.Subject == "" {
+		return errors.New("subject is empty")
+	}
+	if utf8.RuneCountInString(parsed.Subject) > maxSubjectLength {
+		return fmt.Errorf("subject is longer than %d characters", maxSubjectLength)
+	}
+	if parsed.Confidence == nil || *parsed.Confidence < 0 || *parsed.Confidence > 1 {
+		return errors.New("confidence must be a number from 0 to 1")
+	}
+	*a = parsed
+	return nil
+}
+
+// IdentifySubject classifies a content's narration into a subjects row, creating the subject
+// when none matches. The result is stored in meta.subject ({id, name, confidence, ...}, the
+// shape fun-fact generation writes) and the subject's podcasts_count follows the assignment.
+// Answers below minConfidence are only recorded in meta.subject_identification.
+func IdentifySubject(ctx context.Context, jctx JobContext, provider llm.Provider, content db.Content, minConfidence float64) (SubjectMatch, error) {
+	meta, err := utils.DecodeMeta(content.Meta)
+	if err != nil {
+		return SubjectMatch{}, err
+	}
+	text, err := utils.ExtractTextFromMeta(meta)
+	if err != nil {
+		return SubjectMatch{}, err
+	}
+	document := EmbeddingText(content.Title, text)
+
+	candidates, err := jctx.Store.FindSubjectsInText(ctx, document, subjectChoicesLimit)
+	if err != nil {
+		return SubjectMatch{}, err
+	}
+	choices := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		choices = append(choices, strings.TrimSpace(c.Subject))
+	}
+
+	prompt, err := PromptLibrary(jctx.Config).Render("subject_identify", 0, prompts.Vars{Text: document, Choices: choices})
+	if err != nil {
+		return SubjectMatch{}, err
+	}
+	var answer subjectAnswer
+	response, err := llm.GenerateJSON(ctx, provider, llm.Request{
+		System:      prompt.System,
+		User:        prompt.User,
+		Temperature: llm.Float(0),
+	}, jctx.Config.LLMRepairAttempts, answer.decode)
+	if err != nil {
+		return SubjectMatch{}, err
+	}
+	prompt.Record(meta)
+
+	match := SubjectMatch{Confidence: *answer.Confidence}
+	identification := map[string]any{
+		"name":          answer.Subject,
+		"confidence":    match.Confidence,
+		"provider":      response.Provider,
+		"model":         response.Model,
+		"identified_at": time.Now().Format(time.RFC3339),
+	}
+	if match.Confidence < minConfidence {
+		identification["status"] = "low_confidence"
+		meta["subject_identification"] = identification
+		return match, jctx.Store.UpdateContentMeta(ctx, content.ID, meta)
+	}
+
+	if match.Subject, err = jctx.Store.GetSubjectByNameFold(ctx, answer.Subject); err != nil {
+		return SubjectMatch{}, err
+	}
+	if match.Subject.ID == 0 {
+		if err := jctx.Store.InsertSubject(ctx, answer.Subject); err != nil {
+			return SubjectMatch{}, err
+		}
+		if match.Subject, err = jctx.Store.GetSubjectByNameFold(ctx, answer.Subject); err != nil {
+			return SubjectMatch{}, err
+		}
+		match.Created = true
+	}
+	match.Assigned = true
+
+	// Move the usage count from the previous subject, if any.
+	previousID := int64(0)
+	if id, ok := utils.GetValue(meta, "subject", "id"); ok {
+		if n, ok := id.(float64); ok {
+			previousID = int64(n)
+		}
+	}
+	identification["status"] = "assigned"
+	meta["subject_identification"] = identification
+	meta["subject"] = map[string]any{
+		"id":         match.Subject.ID,
+		"name":       match.Subject.Subject,
+		"confidence": match.Confidence,
+		"created":    match.Created,
+	}
+	if err := jctx.Store.UpdateContentMeta(ctx, content.ID, meta); err != nil {
+		return SubjectMatch{}, err
+	}
+	if previousID == match.Subject.ID {
+		return match, nil
+	}
+	if previousID != 0 {
+		if err := jctx.Store.DecrementSubjectPodcasts(ctx, previousID); err != nil {
+			return match, err
+		}
+	}
+	return match, jctx.Store.CountSubjectPodcast(ctx, match.Subject.ID, content.CreatedAt)
+}
@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"ai-things/manager-go/internal/config"
+	"ai-things/manager-go/internal/llm"
+)
+
+// NewLLM returns the provider configured for a use case ([llm] section, e.g. image_prompt=portnumber53:llama3.3).
+func NewLLM(cfg config.Config, useCase string) (llm.Provider, error) {
+	spec, ok := cfg.LLMUseCases[useCase]
+	if !ok {
+		return nil, fmt.Errorf("unknown llm use case %q", useCase)
+	}
+	return llm.New(spec, llmSettings(cfg))
+}
+
+// NewEmbedder returns the embedding provider ([llm] embed=ollama:nomic-embed-text).
+func NewEmbedder(cfg config.Config) (llm.Embedder, error) {
+	return llm.NewEmbedder(cfg.LLMUseCases["embed"], llmSettings(cfg))
+}
+
+func llmSettings(cfg config.Config) llm.Settings {
+	ollamaURL := ""
+	if cfg.OllamaHostname != "" {
+		port := cfg.OllamaPort
+		if port == 0 {
+			port = 11434
+		}
+		ollamaURL = fmt.Sprintf("http://%s:%d", cfg.OllamaHostname, port)
+	}
+	return llm.Settings{
+		OllamaURL:           ollamaURL,
+		OllamaModel:         cfg.OllamaModel,
+		GeminiAPIKey:        cfg.GeminiAPIKey,
+		GeminiModel:         cfg.GeminiModel,
+		Portnumber53URL:     cfg.Portnumber53URL,
+		Portnumber53APIKey:  cfg.Portnumber53APIKey,
+		Portnumber53Model:   cfg.Portnumber53Model,
+		Portnumber53Timeout: time.Duration(cfg.Portnumber53TimeoutSeconds) * time.Second,
+		Timeout:             time.Duration(cfg.LLMTimeoutSeconds) * time.Second,
+		Retries:             cfg.LLMRetries,
+		RetryDelay:          time.Duration(cfg.LLMRetryDelaySeconds) * time.Second,
+	}
+}
@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/music"
+	"ai-things/manager-go/internal/utils"
+)
+
+// mixMusicBed mixes a background track from the configured music library under the narration.
+// It returns the audio file GeneratePodcast should render with (the voice-only mp3 when mixing is
+// disabled or the library has no usable tracks) and records the chosen track in meta.music.
+func mixMusicBed(jctx JobContext, content db.Content, meta map[string]any, voicePath string, duration float64) (string, error) {
+	if strings.TrimSpace(jctx.Config.MusicLibraryFolder) == "" {
+		delete(meta, "music")
+		return voicePath, nil
+	}
+
+	lib, err := music.LoadLibrary(jctx.Config.MusicLibraryFolder)
+	if err != nil {
+		return "", fmt.Errorf("load music library: %w", err)
+	}
+	subject := contentSubjectName(meta)
+	track, mood, ok := lib.Select(subject, content.Title, content.ID)
+	if !ok {
+		utils.Warn("music library has no usable tracks; rendering voice only", "content_id", content.ID, "folder", lib.Dir)
+		delete(meta, "music")
+		return voicePath, nil
+	}
+
+	volume := jctx.Config.MusicVolume
+	if track.Gain > 0 {
+		volume = track.Gain
+	}
+
+	mixedDir := filepath.Join(jctx.Config.BaseOutputFolder, "mixed")
+	if err := utils.EnsureDir(mixedDir); err != nil {
+		return "", err
+	}
+	mixedFilename := fmt.Sprintf("%010d.mp3", content.ID)
+	mixedPath := filepath.Join(mixedDir, mixedFilename)
+
+	cmd, err := music.MixCommand(voicePath, lib.Path(track), mixedPath, music.MixOptions{
+		Duration:    duration,
+		Volume:      volume,
+		FadeSeconds: jctx.Config.MusicFadeSeconds,
+	})
+	if err != nil {
+		return "", err
+	}
+	utils.Info("GeneratePodcast mixing music bed", "content_id", content.ID, "track", track.File, "mood", mood, "subject", subject)
+	if _, err := utils.RunCommand(cmd); err != nil {
+		return "", err
+	}
+
+	mixedSHA, err := utils.SHA256File(mixedPath)
+	if err != nil {
+		return "", err
+	}
+	mixedInfo, err := os.Stat(mixedPath)
+	if err != nil {
+		return "", err
+	}
+
+	meta["music"] = map[string]any{
+		"track":      track.File,
+		"title":      track.Title,
+		"artist":     track.Artist,
+		"license":    track.License,
+		"source_url": track.SourceURL,
+		"mood":       mood,
+		"volume":     volume,
+		"filename":   mixedFilename,
+		"bytes":      mixedInfo.Size(),
+		"hostname":   jctx.Config.Hostname,
+		"sha256":     mixedSHA,
+		"mixed_at":   time.Now().Format(time.RFC3339),
+	}
+	return mixedPath, nil
+}
+
+// contentSubjectName returns the subject recorded in meta.subject, which is either a plain
+// string (legacy) or a map with a "name" key.
+func contentSubjectName(meta map[string]any) string {
+	switch v := meta["subject"].(type) {
+	case string:
+		if v == "pending_ai_analysis" {
+			return ""
+		}
+		return strings.TrimSpace(v)
+	case map[string]any:
+		name, _ := v["name"].(string)
+		return strings.TrimSpace(name)
+	default:
+		return ""
+	}
+}
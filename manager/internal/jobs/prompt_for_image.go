@@ -1,18 +1,17 @@
 package jobs
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/llm"
+	"ai-things/manager-go/internal/prompts"
 	"ai-things/manager-go/internal/utils"
 )
 
@@ -110,43 +109,22 @@ func (j PromptForImageJob) processContent(ctx context.Context, jctx JobContext,
 		return err
 	}
 
-	prompt := buildImagePrompt(text)
-	if jctx.Config.Portnumber53APIKey == "" {
-		return errors.New("missing PORTNUMBER53_API_KEY")
-	}
-
-	requestBody := map[string]any{
-		"model":  "llama3.3",
-		"stream": false,
-		"prompt": prompt,
-	}
-	payload, _ := json.Marshal(requestBody)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://ollama.portnumber53.com/api/generate", bytes.NewReader(payload))
+	provider, err := NewLLM(jctx.Config, "image_prompt")
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-key", jctx.Config.Portnumber53APIKey)
-
-	timeout := jctx.Config.Portnumber53TimeoutSeconds
-	if timeout <= 0 {
-		timeout = 1000
-	}
-	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
-	resp, err := client.Do(req)
+	prompt, err := PromptLibrary(jctx.Config).Render("image_prompt", 0, prompts.Vars{Text: text})
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	var response struct {
-		Response string `json:"response"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	response, err := provider.Generate(ctx, llm.Request{
+		System: prompt.System,
+		User:   prompt.User,
+	})
+	if err != nil {
 		return err
 	}
-	bodyResponse := strings.Trim(response.Response, "\"")
+	bodyResponse := strings.Trim(strings.TrimSpace(response.Text), "\"")
 
 	filename := fmt.Sprintf("%010d.jpg", content.ID)
 	fullPath := filepath.Join(jctx.Config.BaseOutputFolder, "images", filename)
@@ -174,21 +152,18 @@ func (j PromptForImageJob) processContent(ctx context.Context, jctx JobContext,
 		"hostname": jctx.Config.Hostname,
 		"sha256":   sha256sum,
 	}
+	prompt.Record(meta)
 	utils.SetStatus(meta, j.QueueOutput, true)
 
 	return jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta)
 }
 
-func buildImagePrompt(text string) string {
+// buildImagePrompt renders the prompt as a single text, for humans pasting it into a chat model.
+func buildImagePrompt(prompt prompts.Prompt) string {
 	return fmt.Sprintf(`SYSTEM """
--You are an experience designer and artist.
--You are tasked with providing a prompt that will be used to generate an image representing the content of a text.
--The prompt should be a short sentence or two that captures the essence of the text.
-- Do not include any preamble, or comments, or introduction, or explanation, or commentary, or any other additional text.
-- Only output the prompt, nothing else.
-- Make sure to include the name of the place, or subject to help the AI generate an accurate image.
+%s
 """
 USER """
 %s
-"""`, text)
+"""`, prompt.System, prompt.User)
 }
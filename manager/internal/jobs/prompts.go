@@ -0,0 +1,11 @@
+package jobs
+
+import (
+	"ai-things/manager-go/internal/config"
+	"ai-things/manager-go/internal/prompts"
+)
+
+// PromptLibrary returns the prompt templates configured in [prompts].
+func PromptLibrary(cfg config.Config) prompts.Library {
+	return prompts.Library{Dir: cfg.PromptFolder, Pins: cfg.PromptPins}
+}
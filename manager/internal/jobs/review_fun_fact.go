@@ -0,0 +1,337 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/llm"
+	"ai-things/manager-go/internal/prompts"
+	"ai-things/manager-go/internal/slack"
+	"ai-things/manager-go/internal/utils"
+)
+
+// hallucinationRisks orders the risk levels the review prompt may answer with.
+var hallucinationRisks = map[string]int{"low": 1, "medium": 2, "high": 3}
+
+// ReviewFunFactJob fact-checks generated fun facts with a second model before TTS. Facts that pass
+// move on to GenerateWav (funfact_reviewed); the rest are flagged and posted to Slack, where a
+// thumbs-up (handled by Slack:Serve) releases them and a thumbs-down rejects them.
+type ReviewFunFactJob struct {
+	BaseJob
+	MaxWaiting int
+}
+
+func NewReviewFunFactJob() ReviewFunFactJob {
+	return ReviewFunFactJob{
+		BaseJob: BaseJob{
+			QueueInput:      "funfact_created",
+			QueueOutput:     "funfact_reviewed",
+			IgnoreHostCheck: true,
+		},
+		MaxWaiting: 100,
+	}
+}
+
+func (j ReviewFunFactJob) Run(ctx context.Context, jctx JobContext, opts JobOptions) error {
+	if opts.Queue {
+		return j.RunQueue(ctx, jctx, opts, func(ctx context.Context, contentID int64, hostname string) error {
+			return j.processContent(ctx, jctx, contentID, opts.Regenerate)
+		})
+	}
+
+	contentID := opts.ContentID
+	if contentID == 0 {
+		count, err := j.countWaiting(ctx, jctx)
+		if err != nil {
+			return err
+		}
+		utils.Debug("ReviewFunFact waiting", "waiting", count, "max_waiting", j.MaxWaiting)
+		if count >= j.MaxWaiting {
+			utils.Warn("ReviewFunFact too many waiting; sleeping", "sleep_s", 60, "waiting", count, "max_waiting", j.MaxWaiting)
+			time.Sleep(60 * time.Second)
+			return nil
+		}
+
+		content, err := j.selectNext(ctx, jctx)
+		if err != nil {
+			return err
+		}
+		contentID = content.ID
+	}
+
+	return j.processContent(ctx, jctx, contentID, opts.Regenerate)
+}
+
+func (j ReviewFunFactJob) countWaiting(ctx context.Context, jctx JobContext) (int, error) {
+	where := "WHERE type = 'gemini.payload'"
+	finishedTrue := db.StatusTrueCondition([]string{j.QueueOutput})
+	finishedFalse := db.StatusNotTrueCondition([]string{"wav_generated"})
+	if finishedTrue != "" {
+		where += " AND " + finishedTrue
+	}
+	if finishedFalse != "" {
+		where += " AND " + finishedFalse
+	}
+	return jctx.Store.CountContent(ctx, where)
+}
+
+func (j ReviewFunFactJob) selectNext(ctx context.Context, jctx JobContext) (db.Content, error) {
+	where := "WHERE type = 'gemini.payload'"
+	trueFlags := db.StatusTrueCondition([]string{"funfact_created"})
+	// Content already past TTS predates this stage; flagged content waits for a human.
+	falseFlags := db.StatusNotTrueCondition([]string{j.QueueOutput, "funfact_flagged", "funfact_rejected", "wav_generated"})
+	if trueFlags != "" {
+		where += " AND " + trueFlags
+	}
+	if falseFlags != "" {
+		where += " AND " + falseFlags
+	}
+	content, err := jctx.Store.FindFirstContent(ctx, where)
+	if err != nil {
+		return db.Content{}, err
+	}
+	if content.ID == 0 {
+		return db.Content{}, errors.New("no content to process")
+	}
+	return content, nil
+}
+
+// factReview is the JSON the funfact_review prompt asks for.
+type factReview struct {
+	Accuracy          *float64 `json:"accuracy"`
+	HallucinationRisk string   `json:"hallucination_risk"`
+	PolicyIssues      []string `json:"policy_issues"`
+	Reasons           []string `json:"reasons"`
+}
+
+func (r *factReview) decode(text string) error {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return errors.New("no JSON object in response")
+	}
+	var parsed factReview
+	if err := json.Unmarshal([]byte(text[start:end+1]), &parsed); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if parsed.Accuracy == nil || *parsed.Accuracy < 0 || *parsed.Accuracy > 10 {
+		return errors.New("accuracy must be a number from 0 to 10")
+	}
+	parsed.HallucinationRisk = strings.ToLower(strings.TrimSpace(parsed.HallucinationRisk))
+	if _, ok := hallucinationRisks[parsed.HallucinationRisk]; !ok {
+		return errors.New(`hallucination_risk must be "low", "medium" or "high"`)
+	}
+	var issues []string
+	for _, issue := range parsed.PolicyIssues {
+		if issue = strings.TrimSpace(issue); issue != "" && !strings.EqualFold(issue, "none") {
+			issues = append(issues, issue)
+		}
+	}
+	parsed.PolicyIssues = issues
+	*r = parsed
+	return nil
+}
+
+// failures lists the configured thresholds the review does not meet.
+func (r factReview) failures(minAccuracy float64, maxRisk string) []string {
+	var out []string
+	if *r.Accuracy < minAccuracy {
+		out = append(out, fmt.Sprintf("accuracy %.1f below %.1f", *r.Accuracy, minAccuracy))
+	}
+	if limit, ok := hallucinationRisks[maxRisk]; ok && hallucinationRisks[r.HallucinationRisk] > limit {
+		out = append(out, fmt.Sprintf("hallucination risk %s above %s", r.HallucinationRisk, maxRisk))
+	}
+	if len(r.PolicyIssues) > 0 {
+		out = append(out, "policy issues: "+strings.Join(r.PolicyIssues, "; "))
+	}
+	return out
+}
+
+func (j ReviewFunFactJob) processContent(ctx context.Context, jctx JobContext, contentID int64, regenerate bool) error {
+	utils.Info("ReviewFunFact process", "content_id", contentID, "regenerate", regenerate)
+	cfg := jctx.Config
+	content, err := jctx.Store.GetContentByID(ctx, contentID)
+	if err != nil {
+		return err
+	}
+	meta, err := utils.DecodeMeta(content.Meta)
+	if err != nil {
+		return err
+	}
+
+	if !regenerate {
+		if reviewed, _ := utils.GetStatus(meta, j.QueueOutput); reviewed {
+			utils.Info("ReviewFunFact already reviewed; skipping", "content_id", contentID)
+			return nil
+		}
+		if flagged, _ := utils.GetStatus(meta, "funfact_flagged"); flagged {
+			// A previous run may have flagged it without reaching Slack.
+			if _, posted := utils.GetMap(meta, "slack_funfact_review_request"); cfg.ReviewSlack && !posted {
+				return j.requestHumanReview(ctx, jctx, content, meta)
+			}
+			utils.Info("ReviewFunFact flagged; waiting for human review", "content_id", contentID)
+			return nil
+		}
+	}
+
+	text, err := utils.ExtractTextFromMeta(meta)
+	if err != nil {
+		return err
+	}
+	document := fmt.Sprintf("TITLE: %s\n\n%s", strings.TrimSpace(content.Title), text)
+	if sources, ok := utils.GetValue(meta, "funfact", "sources"); ok {
+		if list, _ := sources.([]any); len(list) > 0 {
+			document += "\n\nCLAIMED SOURCES:"
+			for _, source := range list {
+				document += fmt.Sprintf("\n- %v", source)
+			}
+		}
+	}
+	subject, _ := utils.GetString(meta, "subject", "name")
+
+	provider, err := NewLLM(cfg, "review")
+	if err != nil {
+		return err
+	}
+	prompt, err := PromptLibrary(cfg).Render("funfact_review", 0, prompts.Vars{Subject: subject, Text: document})
+	if err != nil {
+		return err
+	}
+	var review factReview
+	response, err := llm.GenerateJSON(ctx, provider, llm.Request{
+		System:      prompt.System,
+		User:        prompt.User,
+		Temperature: llm.Float(0),
+	}, cfg.LLMRepairAttempts, review.decode)
+	if err != nil {
+		return err
+	}
+
+	failures := review.failures(cfg.ReviewMinAccuracy, cfg.ReviewMaxHallucinationRisk)
+	meta["review"] = map[string]any{
+		"accuracy":           *review.Accuracy,
+		"hallucination_risk": review.HallucinationRisk,
+		"policy_issues":      review.PolicyIssues,
+		"reasons":            review.Reasons,
+		"passed":             len(failures) == 0,
+		"failures":           failures,
+		"provider":           response.Provider,
+		"model":              response.Model,
+		"reviewed_at":        time.Now().Format(time.RFC3339),
+	}
+	prompt.Record(meta)
+
+	if len(failures) == 0 {
+		utils.Info("ReviewFunFact passed", "content_id", contentID, "accuracy", *review.Accuracy, "hallucination_risk", review.HallucinationRisk)
+		utils.SetStatus(meta, j.QueueOutput, true)
+		utils.SetStatus(meta, "funfact_flagged", false)
+		if err := jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta); err != nil {
+			return err
+		}
+		payload, _ := json.Marshal(QueuePayload{ContentID: content.ID, Hostname: ""})
+		return jctx.Queue.Publish(j.QueueOutput, payload)
+	}
+
+	utils.Warn("ReviewFunFact flagged", "content_id", contentID, "failures", strings.Join(failures, ", "))
+	utils.SetStatus(meta, "funfact_flagged", true)
+	// A --regenerate run can fail content that passed before; keep it away from GenerateWav.
+	utils.SetStatus(meta, j.QueueOutput, false)
+	if err := jctx.Store.UpdateContentMetaStatus(ctx, content.ID, "funfact_flagged", meta); err != nil {
+		return err
+	}
+	if !cfg.ReviewSlack {
+		return nil
+	}
+	return j.requestHumanReview(ctx, jctx, content, meta)
+}
+
+// requestHumanReview posts a flagged fact to Slack and records the thread in
+// meta.slack_funfact_review_request so Slack:Serve can match the reaction.
+func (j ReviewFunFactJob) requestHumanReview(ctx context.Context, jctx JobContext, content db.Content, meta map[string]any) error {
+	teamID, channelID, err := slackReviewChannel(ctx, jctx)
+	if err != nil {
+		return err
+	}
+	token, err := jctx.Store.GetSlackBotToken(ctx, teamID)
+	if err != nil || token == "" {
+		return fmt.Errorf("missing slack bot token for team_id=%s (install the Slack app first)", teamID)
+	}
+
+	review, _ := utils.GetMap(meta, "review")
+	var root strings.Builder
+	fmt.Fprintf(&root, "Fun fact flagged by review:\n%010d - %s\n", content.ID, strings.TrimSpace(content.Title))
+	fmt.Fprintf(&root, "Accuracy: %v/10, hallucination risk: %v\n", review["accuracy"], review["hallucination_risk"])
+	// The review is []string when just written and []any when read back from the DB.
+	for _, key := range []string{"failures", "reasons"} {
+		switch items := review[key].(type) {
+		case []string:
+			for _, item := range items {
+				fmt.Fprintf(&root, "• %s\n", item)
+			}
+		case []any:
+			for _, item := range items {
+				fmt.Fprintf(&root, "• %v\n", item)
+			}
+		}
+	}
+	root.WriteString("\nReact with :thumbsup: to send it to TTS or :thumbsdown: to reject it.")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	if err := slack.JoinChannel(ctx, client, token, channelID); err != nil {
+		utils.Warn("ReviewFunFact slack join failed", "channel_id", channelID, "err", err)
+	}
+	threadTS, err := slack.PostMessageWithTS(ctx, client, token, channelID, root.String(), "")
+	if err != nil {
+		return err
+	}
+	if text, err := utils.ExtractTextFromMeta(meta); err == nil {
+		if runes := []rune(text); len(runes) > 3500 {
+			text = string(runes[:3500]) + "…"
+		}
+		if err := slack.PostMessage(ctx, client, token, channelID, text, threadTS); err != nil {
+			utils.Warn("ReviewFunFact slack text reply failed", "content_id", content.ID, "err", err)
+		}
+	}
+
+	meta["slack_funfact_review_request"] = map[string]any{
+		"team_id":    teamID,
+		"channel_id": channelID,
+		"thread_ts":  threadTS,
+		"hostname":   jctx.Config.Hostname,
+		"created_at": time.Now().Format(time.RFC3339),
+		"content_id": content.ID,
+	}
+	utils.Info("ReviewFunFact posted for human review", "content_id", content.ID, "channel_id", channelID, "thread_ts", threadTS)
+	return jctx.Store.UpdateContentMeta(ctx, content.ID, meta)
+}
+
+// slackReviewChannel resolves the Slack team and the channel review threads are posted to.
+func slackReviewChannel(ctx context.Context, jctx JobContext) (string, string, error) {
+	teamID := strings.TrimSpace(jctx.Config.SlackTeamID)
+	if teamID == "" {
+		detected, err := jctx.Store.GetDefaultSlackTeamID(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		if teamID = detected; teamID == "" {
+			return "", "", errors.New("missing slack.team_id and no slack installation found in DB (run Slack:Serve install flow first, or set slack.team_id)")
+		}
+	}
+	channelID := strings.TrimSpace(jctx.Config.SlackImageChannel)
+	if channelID == "" {
+		stored, err := jctx.Store.GetSlackImageChannel(ctx, teamID)
+		if err != nil {
+			return "", "", err
+		}
+		if channelID = stored; channelID == "" {
+			return "", "", errors.New("missing slack.image_channel and no stored channel found in DB (run Slack:CreateImageChannel --name=ai-images or set slack.image_channel)")
+		}
+	}
+	return teamID, channelID, nil
+}
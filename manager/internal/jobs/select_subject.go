@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"ai-things/manager-go/internal/config"
+	"ai-things/manager-go/internal/db"
+)
+
+// SelectSubject picks the subject for the next fun fact with the configured strategy
+// ([subjects] strategy). It returns a zero Subject when no active subject is eligible.
+func SelectSubject(ctx context.Context, jctx JobContext) (db.Subject, error) {
+	switch jctx.Config.SubjectStrategy {
+	case "weighted", "least_used", "random", "unused":
+	default:
+		return db.Subject{}, fmt.Errorf("unknown subjects strategy %q", jctx.Config.SubjectStrategy)
+	}
+	subjects, err := jctx.Store.ListSubjects(ctx, true)
+	if err != nil {
+		return db.Subject{}, err
+	}
+	return pickSubject(jctx.Config, subjects, time.Now(), rand.Float64), nil
+}
+
+// SubjectWeight is the relative chance of a subject being picked, before the cooldown; 0 means
+// the strategy never picks it.
+func SubjectWeight(cfg config.Config, subject db.Subject, now time.Time) float64 {
+	if !subject.IsActive {
+		return 0
+	}
+	switch cfg.SubjectStrategy {
+	case "unused":
+		if subject.PodcastsCount > 0 {
+			return 0
+		}
+		return 1
+	case "weighted":
+		usage := math.Pow(1/float64(1+subject.PodcastsCount), cfg.SubjectUsageWeight)
+		weight := usage * math.Pow(subjectFreshness(cfg, subject, now), cfg.SubjectFreshnessWeight)
+		// Unscored subjects count as typical (1).
+		if subject.Score != nil && *subject.Score > 0 {
+			weight *= math.Pow(*subject.Score, cfg.SubjectPerformanceWeight)
+		}
+		return weight
+	default:
+		return 1
+	}
+}
+
+// SubjectInCooldown reports a subject used less than [subjects] cooldown_hours ago.
+func SubjectInCooldown(cfg config.Config, subject db.Subject, now time.Time) bool {
+	if subject.LastUsedAt == nil || cfg.SubjectCooldownHours <= 0 {
+		return false
+	}
+	return now.Sub(*subject.LastUsedAt) < time.Duration(cfg.SubjectCooldownHours*float64(time.Hour))
+}
+
+// subjectFreshness decays from 1.5 (just seen in an article) towards 0.5, halving the bonus every
+// SubjectFreshnessDays.
+func subjectFreshness(cfg config.Config, subject db.Subject, now time.Time) float64 {
+	if cfg.SubjectFreshnessDays <= 0 {
+		return 1
+	}
+	seen := subject.CreatedAt
+	if subject.LastSeenAt != nil {
+		seen = *subject.LastSeenAt
+	}
+	days := max(now.Sub(seen).Hours()/24, 0)
+	return 0.5 + math.Exp2(-days/cfg.SubjectFreshnessDays)
+}
+
+// pickSubject draws a subject proportionally to its weight among those out of cooldown. When
+// every candidate is cooling down the least recently used one is returned, so generation does not
+// stall on a small table.
+func pickSubject(cfg config.Config, subjects []db.Subject, now time.Time, random func() float64) db.Subject {
+	var eligible, cooling []db.Subject
+	var weights []float64
+	for _, subject := range subjects {
+		weight := SubjectWeight(cfg, subject, now)
+		if weight <= 0 {
+			continue
+		}
+		if SubjectInCooldown(cfg, subject, now) {
+			cooling = append(cooling, subject)
+			continue
+		}
+		eligible = append(eligible, subject)
+		weights = append(weights, weight)
+	}
+
+	if len(eligible) == 0 {
+		var oldest db.Subject
+		for _, subject := range cooling {
+			if oldest.ID == 0 || subject.LastUsedAt.Before(*oldest.LastUsedAt) {
+				oldest = subject
+			}
+		}
+		return oldest
+	}
+
+	if cfg.SubjectStrategy == "least_used" {
+		fewest := eligible[0].PodcastsCount
+		for _, subject := range eligible {
+			fewest = min(fewest, subject.PodcastsCount)
+		}
+		for i, subject := range eligible {
+			if subject.PodcastsCount > fewest {
+				weights[i] = 0
+			}
+		}
+	}
+
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	target := random() * total
+	for i, weight := range weights {
+		if target < weight {
+			return eligible[i]
+		}
+		target -= weight
+	}
+	// Rounding can leave target just above the last weight.
+	for i := len(eligible) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return eligible[i]
+		}
+	}
+	return db.Subject{}
+}
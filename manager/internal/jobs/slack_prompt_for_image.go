@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/prompts"
 	"ai-things/manager-go/internal/slack"
 	"ai-things/manager-go/internal/utils"
 )
@@ -157,7 +158,11 @@ func (j SlackPromptForImageJob) processContent(ctx context.Context, jctx JobCont
 	if err != nil {
 		return err
 	}
-	prompt := buildImagePrompt(text)
+	rendered, err := PromptLibrary(cfg).Render("image_prompt", 0, prompts.Vars{Text: text})
+	if err != nil {
+		return err
+	}
+	prompt := buildImagePrompt(rendered)
 
 	client := &http.Client{Timeout: 20 * time.Second}
 
@@ -229,6 +234,7 @@ func (j SlackPromptForImageJob) processContent(ctx context.Context, jctx JobCont
 
 	meta["slack_image_request"] = newReq
 	meta["slack_image_requests"] = history
+	rendered.Record(meta)
 	utils.SetStatus(meta, j.QueueOutput, true)
 
 	// Don’t claim thumbnail_generated yet — Slack:Serve will finalize when an image is uploaded.
@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"ai-things/manager-go/internal/db"
+)
+
+const (
+	// A like or comment is worth this many views when scoring a video.
+	likePoints    = 10
+	commentPoints = 25
+	// scorePrior adds this many typical (score 1) videos to every subject, so a subject with a
+	// single lucky video does not jump to the top.
+	scorePrior = 2
+	// Scores are clamped so performance cannot drown the other selection factors.
+	minSubjectScore = 0.25
+	maxSubjectScore = 4
+)
+
+// RollupSubjectScores recomputes subjects.score from the video statistics stored in contents
+// meta and returns the number of subjects scored.
+func RollupSubjectScores(ctx context.Context, jctx JobContext) (int, error) {
+	stats, err := jctx.Store.ListSubjectVideoStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	minAge := time.Duration(jctx.Config.YoutubeScoreMinAgeDays * 24 * float64(time.Hour))
+	scores, videos := subjectScores(stats, time.Now(), minAge)
+	if err := jctx.Store.ReplaceSubjectScores(ctx, scores, videos); err != nil {
+		return 0, err
+	}
+	return len(scores), nil
+}
+
+// subjectScores compares each video's engagement (views, likes, comments) with the median video,
+// then takes the geometric mean per subject, shrunk towards 1 by scorePrior. Videos published
+// less than minAge ago (or without a publish date) are skipped while they are still gathering
+// views.
+func subjectScores(stats []db.SubjectVideoStats, now time.Time, minAge time.Duration) (map[int64]float64, map[int64]int) {
+	var counted []db.SubjectVideoStats
+	for _, v := range stats {
+		if v.PublishedAt == nil || now.Sub(*v.PublishedAt) < minAge {
+			continue
+		}
+		counted = append(counted, v)
+	}
+	scores := map[int64]float64{}
+	videos := map[int64]int{}
+	if len(counted) == 0 {
+		return scores, videos
+	}
+
+	points := make([]float64, len(counted))
+	for i, v := range counted {
+		points[i] = float64(v.Views + likePoints*v.Likes + commentPoints*v.Comments)
+	}
+	sorted := append([]float64(nil), points...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	logSums := map[int64]float64{}
+	for i, v := range counted {
+		logSums[v.SubjectID] += math.Log((1 + points[i]) / (1 + median))
+		videos[v.SubjectID]++
+	}
+	for id, sum := range logSums {
+		score := math.Exp(sum / float64(videos[id]+scorePrior))
+		scores[id] = min(max(score, minSubjectScore), maxSubjectScore)
+	}
+	return scores, videos
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"time"
+
+	"ai-things/manager-go/internal/config"
+	"ai-things/manager-go/internal/subtitles"
+	"ai-things/manager-go/internal/utils"
+)
+
+// subtitleStyle resolves the configured caption style profile and applies config overrides.
+func subtitleStyle(cfg config.Config) subtitles.Style {
+	style, ok := subtitles.StyleProfile(cfg.SubtitleStyle)
+	if !ok {
+		utils.Warn("unknown subtitle style; using vertical", "style", cfg.SubtitleStyle)
+		style, _ = subtitles.StyleProfile("vertical")
+	}
+	if cfg.SubtitleMaxCharsPerLine > 0 {
+		style.MaxCharsPerLine = cfg.SubtitleMaxCharsPerLine
+	}
+	if cfg.SubtitleMaxLines > 0 {
+		style.MaxLines = cfg.SubtitleMaxLines
+	}
+	if cfg.SubtitleMinDuration > 0 {
+		style.MinDuration = time.Duration(cfg.SubtitleMinDuration * float64(time.Second))
+	}
+	if cfg.SubtitleMaxDuration > 0 {
+		style.MaxDuration = time.Duration(cfg.SubtitleMaxDuration * float64(time.Second))
+	}
+	if cfg.SubtitleMaxCPS > 0 {
+		style.MaxCPS = cfg.SubtitleMaxCPS
+	}
+	return style
+}
@@ -0,0 +1,232 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/db"
+	"ai-things/manager-go/internal/llm"
+	"ai-things/manager-go/internal/subtitles"
+	"ai-things/manager-go/internal/utils"
+)
+
+// translateBatchSize keeps each LLM request small enough that models return every cue.
+const translateBatchSize = 40
+
+type TranslateSubtitlesJob struct {
+	BaseJob
+	MaxWaiting int
+}
+
+func NewTranslateSubtitlesJob() TranslateSubtitlesJob {
+	return TranslateSubtitlesJob{
+		BaseJob: BaseJob{
+			QueueInput:  "srt_fixed",
+			QueueOutput: "subtitles_translated",
+			// Translation only talks to the LLM; any host can pick it up.
+			IgnoreHostCheck: true,
+		},
+		MaxWaiting: 100,
+	}
+}
+
+func (j TranslateSubtitlesJob) Run(ctx context.Context, jctx JobContext, opts JobOptions) error {
+	if len(jctx.Config.SubtitleTranslateLanguages) == 0 {
+		return errors.New("no subtitle target languages configured (set subtitles.translate_languages)")
+	}
+	if opts.Queue {
+		return j.RunQueue(ctx, jctx, opts, func(ctx context.Context, contentID int64, hostname string) error {
+			return j.processContent(ctx, jctx, contentID, false)
+		})
+	}
+
+	contentID := opts.ContentID
+	if contentID == 0 {
+		count, err := j.countWaiting(ctx, jctx)
+		if err != nil {
+			return err
+		}
+		utils.Debug("TranslateSubtitles waiting", "waiting", count, "max_waiting", j.MaxWaiting)
+		if count >= j.MaxWaiting {
+			utils.Warn("TranslateSubtitles too many waiting; sleeping", "sleep_s", 60, "waiting", count, "max_waiting", j.MaxWaiting)
+			time.Sleep(60 * time.Second)
+			return nil
+		}
+
+		content, err := j.selectNext(ctx, jctx)
+		if err != nil {
+			return err
+		}
+		contentID = content.ID
+	}
+
+	return j.processContent(ctx, jctx, contentID, opts.Regenerate)
+}
+
+func (j TranslateSubtitlesJob) where() string {
+	where := "WHERE " + db.StatusTrueCondition([]string{"srt_fixed"})
+	notTranslated := db.StatusNotTrueCondition([]string{j.QueueOutput})
+	if notTranslated != "" {
+		where += " AND " + notTranslated
+	}
+	return where
+}
+
+func (j TranslateSubtitlesJob) countWaiting(ctx context.Context, jctx JobContext) (int, error) {
+	return jctx.Store.CountContent(ctx, j.where())
+}
+
+func (j TranslateSubtitlesJob) selectNext(ctx context.Context, jctx JobContext) (db.Content, error) {
+	content, err := jctx.Store.FindFirstContent(ctx, j.where())
+	if err != nil {
+		return db.Content{}, err
+	}
+	if content.ID == 0 {
+		return db.Content{}, errors.New("no content to process")
+	}
+	return content, nil
+}
+
+func (j TranslateSubtitlesJob) processContent(ctx context.Context, jctx JobContext, contentID int64, regenerate bool) error {
+	utils.Info("TranslateSubtitles process", "content_id", contentID, "regenerate", regenerate)
+	content, err := jctx.Store.GetContentByID(ctx, contentID)
+	if err != nil {
+		return err
+	}
+	meta, err := utils.DecodeMeta(content.Meta)
+	if err != nil {
+		return err
+	}
+
+	subtitlesMeta, ok := meta["subtitles"].(map[string]any)
+	if !ok {
+		return errors.New("subtitles missing")
+	}
+	srt, _ := subtitlesMeta["srt"].(string)
+	if srt == "" {
+		return errors.New("srt content missing")
+	}
+	captions, err := subtitles.ParseSRT(srt)
+	if err != nil {
+		if len(captions) == 0 {
+			return err
+		}
+		utils.Warn("TranslateSubtitles skipped unparseable caption blocks", "content_id", content.ID, "err", err)
+	}
+
+	tracks, _ := subtitlesMeta["tracks"].(map[string]any)
+	if tracks == nil {
+		tracks = map[string]any{}
+	}
+	provider, err := NewLLM(jctx.Config, "translate")
+	if err != nil {
+		return err
+	}
+	model := provider.Name() + ":" + provider.Model()
+
+	for _, lang := range jctx.Config.SubtitleTranslateLanguages {
+		if _, done := tracks[lang]; done && !regenerate {
+			utils.Debug("TranslateSubtitles track exists; skipping", "content_id", content.ID, "lang", lang)
+			continue
+		}
+		translated, err := translateCaptions(ctx, provider, lang, captions)
+		if err != nil {
+			return fmt.Errorf("translate %s: %w", lang, err)
+		}
+		tracks[lang] = map[string]any{
+			"language":      lang,
+			"srt":           subtitles.SerializeSRT(translated),
+			"model":         model,
+			"translated_at": time.Now().Format(time.RFC3339),
+		}
+		utils.Info("TranslateSubtitles track ready", "content_id", content.ID, "lang", lang, "captions", len(translated))
+	}
+
+	subtitlesMeta["tracks"] = tracks
+	meta["subtitles"] = subtitlesMeta
+	utils.SetStatus(meta, j.QueueOutput, true)
+
+	// Keep contents.status at the main pipeline stage; translation runs alongside it.
+	if err := jctx.Store.UpdateContentMeta(ctx, content.ID, meta); err != nil {
+		return err
+	}
+
+	payload, _ := json.Marshal(QueuePayload{ContentID: content.ID, Hostname: jctx.Config.Hostname})
+	return jctx.Queue.Publish(j.QueueOutput, payload)
+}
+
+// translateCaptions translates caption texts in batches and returns captions with the original
+// timings. The model gets a JSON array of cue texts and must return an array of the same length,
+// so cue boundaries (and therefore timings) never move.
+func translateCaptions(ctx context.Context, provider llm.Provider, lang string, captions []subtitles.Caption) ([]subtitles.Caption, error) {
+	out := make([]subtitles.Caption, len(captions))
+	copy(out, captions)
+	for start := 0; start < len(captions); start += translateBatchSize {
+		end := min(start+translateBatchSize, len(captions))
+		texts := make([]string, 0, end-start)
+		for _, c := range captions[start:end] {
+			texts = append(texts, strings.ReplaceAll(c.Text, "\n", " "))
+		}
+
+		var translated []string
+		var err error
+		// The provider retries transport failures; this retries answers with the wrong shape.
+		for attempt := 1; attempt <= 2; attempt++ {
+			translated, err = translateTexts(ctx, provider, lang, texts)
+			if err == nil {
+				break
+			}
+			utils.Warn("TranslateSubtitles batch failed", "lang", lang, "attempt", attempt, "err", err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i, text := range translated {
+			out[start+i].Text = text
+		}
+	}
+	return out, nil
+}
+
+func translateTexts(ctx context.Context, provider llm.Provider, lang string, texts []string) ([]string, error) {
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, err
+	}
+	prompt := fmt.Sprintf(`Translate each subtitle line in the JSON array below into the language with code %q.
+Keep the meaning and tone, keep each line short, and do not merge or split lines.
+Respond with JSON only, in the form {"lines": ["...", "..."]}, with exactly %d lines in the same order.
+
+%s`, lang, len(texts), input)
+
+	utils.Debug("llm translate", "provider", provider.Name(), "model", provider.Model(), "lang", lang, "lines", len(texts))
+	response, err := provider.Generate(ctx, llm.Request{
+		User:        prompt,
+		JSON:        true,
+		Temperature: llm.Float(0.2),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var parsed struct {
+		Lines []string `json:"lines"`
+	}
+	if err := json.Unmarshal([]byte(response.Text), &parsed); err != nil {
+		return nil, fmt.Errorf("decode translation: %w", err)
+	}
+	if len(parsed.Lines) != len(texts) {
+		return nil, fmt.Errorf("translation returned %d lines, want %d", len(parsed.Lines), len(texts))
+	}
+	for i, line := range parsed.Lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return nil, fmt.Errorf("translation line %d is empty", i+1)
+		}
+		parsed.Lines[i] = line
+	}
+	return parsed.Lines, nil
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"ai-things/manager-go/internal/transcribe"
+	"ai-things/manager-go/internal/utils"
+)
+
+var (
+	silenceStartRegex = regexp.MustCompile(`silence_start: (-?[\d.]+)`)
+	silenceEndRegex   = regexp.MustCompile(`silence_end: (-?[\d.]+)`)
+)
+
+// detectSentenceSegments finds where each sentence is spoken in a TTS wav. The TTS inserts
+// ttsSentenceSilence between sentences, so the speech runs between detected silences map 1:1 to
+// the script sentences. It returns nil (and callers fall back to estimated timings) when the
+// number of speech runs does not match the number of sentences.
+func detectSentenceSegments(wavPath, text string) ([]map[string]any, error) {
+	sentences := transcribe.SplitSentences(text)
+	if len(sentences) == 0 {
+		return nil, nil
+	}
+	duration, err := probeDuration(wavPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Detect pauses a bit shorter than the sentence silence, but longer than comma pauses.
+	minSilence := ttsSentenceSilence.Seconds() * 0.7
+	cmd := fmt.Sprintf("ffmpeg -hide_banner -nostats -i %s -af silencedetect=noise=-45dB:d=%s -f null - 2>&1",
+		utils.ShellEscape(wavPath),
+		strconv.FormatFloat(minSilence, 'f', 2, 64),
+	)
+	output, err := utils.RunCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := silenceStartRegex.FindAllStringSubmatch(output, -1)
+	ends := silenceEndRegex.FindAllStringSubmatch(output, -1)
+	type span struct{ start, end float64 }
+	var silences []span
+	for i, m := range starts {
+		start, _ := strconv.ParseFloat(m[1], 64)
+		end := duration
+		if i < len(ends) {
+			end, _ = strconv.ParseFloat(ends[i][1], 64)
+		}
+		silences = append(silences, span{start: max(start, 0), end: end})
+	}
+
+	// Speech is whatever lies between silences.
+	var speech []span
+	at := 0.0
+	for _, s := range silences {
+		if s.start-at > 0.1 {
+			speech = append(speech, span{start: at, end: s.start})
+		}
+		at = s.end
+	}
+	if duration-at > 0.1 {
+		speech = append(speech, span{start: at, end: duration})
+	}
+
+	if len(speech) != len(sentences) {
+		utils.Warn("TTS segment detection mismatch; timings will be estimated",
+			"path", wavPath,
+			"speech_runs", len(speech),
+			"sentences", len(sentences),
+		)
+		return nil, nil
+	}
+
+	segments := make([]map[string]any, 0, len(sentences))
+	for i, sentence := range sentences {
+		segments = append(segments, map[string]any{
+			"text":  sentence,
+			"start": speech[i].start,
+			"end":   speech[i].end,
+		})
+	}
+	return segments, nil
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"ai-things/manager-go/internal/db"
 	"ai-things/manager-go/internal/utils"
+	"ai-things/manager-go/internal/youtube"
 )
 
 type UploadYouTubeJob struct {
@@ -64,9 +67,19 @@ func (j UploadYouTubeJob) Run(ctx context.Context, jctx JobContext, opts JobOpti
 	return j.processContent(ctx, jctx, contentID, opts.Info, opts.EasyUpload)
 }
 
+// readyFlags are the statuses content needs before upload. With translation configured it also
+// waits for TranslateSubtitles, since tracks translated after the upload are never attached.
+func (j UploadYouTubeJob) readyFlags(jctx JobContext) []string {
+	flags := []string{"funfact_created", "wav_generated", "mp3_generated", "srt_generated", "thumbnail_generated", "podcast_ready", "youtube_approved"}
+	if len(jctx.Config.SubtitleTranslateLanguages) > 0 {
+		flags = append(flags, "subtitles_translated")
+	}
+	return flags
+}
+
 func (j UploadYouTubeJob) countWaiting(ctx context.Context, jctx JobContext) (int, error) {
 	where := "WHERE type = 'gemini.payload'"
-	trueFlags := db.StatusTrueCondition([]string{"funfact_created", "wav_generated", "mp3_generated", "srt_generated", "thumbnail_generated", "podcast_ready", "youtube_approved"})
+	trueFlags := db.StatusTrueCondition(j.readyFlags(jctx))
 	// Treat "not uploaded yet" as "not true" (NULL or anything other than 'true'),
 	// matching selectNext(). Using StatusFalseCondition would require an explicit 'false' value.
 	notTrue := db.StatusNotTrueCondition([]string{"youtube_uploaded"})
@@ -89,7 +102,7 @@ func (j UploadYouTubeJob) countWaiting(ctx context.Context, jctx JobContext) (in
 
 func (j UploadYouTubeJob) selectNext(ctx context.Context, jctx JobContext) (db.Content, error) {
 	where := "WHERE type = 'gemini.payload'"
-	trueFlags := db.StatusTrueCondition([]string{"funfact_created", "wav_generated", "mp3_generated", "srt_generated", "thumbnail_generated", "podcast_ready", "youtube_approved"})
+	trueFlags := db.StatusTrueCondition(j.readyFlags(jctx))
 	notTrue := db.StatusNotTrueCondition([]string{"youtube_uploaded"})
 	notRejected := db.StatusNotTrueCondition([]string{"youtube_rejected"})
 	missing := db.MetaKeyMissingCondition([]string{"video_id.v1"})
@@ -271,8 +284,40 @@ func (j UploadYouTubeJob) processContent(ctx context.Context, jctx JobContext, c
 		return jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta)
 	}
 
+	var videoID string
+	switch jctx.Config.YoutubeUploader {
+	case "api":
+		video := youtube.Video{Title: title, Description: description, CategoryID: category, PrivacyStatus: privacyStatus}
+		if keywords != "" {
+			video.Tags = strings.Split(keywords, ",")
+		}
+		videoID, err = uploadYouTubeAPI(ctx, jctx, filename, video, meta)
+	case "script":
+		videoID, err = uploadYouTubeScript(jctx, content.ID, filename, title, description, category, keywords, privacyStatus, meta)
+	default:
+		err = fmt.Errorf("unknown youtube uploader %q", jctx.Config.YoutubeUploader)
+	}
+	if err != nil {
+		return err
+	}
+
+	meta["video_id.v1"] = videoID
+	utils.SetStatus(meta, j.QueueOutput, true)
+	utils.SetStatus(meta, "youtube_uploaded", true)
+
+	return jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta)
+}
+
+// uploadYouTubeScript runs paths.youtube_upload_script and reads the video and caption ids from
+// its output.
+func uploadYouTubeScript(jctx JobContext, contentID int64, filename, title, description, category, keywords, privacyStatus string, meta map[string]any) (string, error) {
+	captionArgs, err := writeCaptionTracks(jctx, contentID, meta)
+	if err != nil {
+		return "", err
+	}
+
 	command := fmt.Sprintf(
-		"cd %s && %s --file=%s --title=%s --description=%s --category=%s --keywords=\"%s\" --privacyStatus=%s",
+		"cd %s && %s --file=%s --title=%s --description=%s --category=%s --keywords=\"%s\" --privacyStatus=%s%s",
 		utils.ShellEscape(resolveWorkDir([]string{
 			filepath.Join(jctx.Config.BaseAppFolder, "auto-subtitles-generator"),
 			filepath.Join("..", "auto-subtitles-generator"),
@@ -285,24 +330,130 @@ func (j UploadYouTubeJob) processContent(ctx context.Context, jctx JobContext, c
 		utils.ShellEscape(category),
 		strings.ReplaceAll(keywords, "\"", "\\\""),
 		utils.ShellEscape(privacyStatus),
+		captionArgs,
 	)
 
 	output, err := utils.RunCommand(command)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	pattern := regexp.MustCompile("Video id '([^']+)' was successfully uploaded")
 	matches := pattern.FindStringSubmatch(output)
 	if len(matches) < 2 {
-		return errors.New("video ID not found in upload output")
+		return "", errors.New("video ID not found in upload output")
 	}
+	recordUploadedCaptions(meta, output)
+	return matches[1], nil
+}
 
-	meta["video_id.v1"] = matches[1]
-	utils.SetStatus(meta, j.QueueOutput, true)
-	utils.SetStatus(meta, "youtube_uploaded", true)
+// uploadYouTubeAPI uploads with the native YouTube client, attaches the caption tracks and records
+// the upload in meta.youtube.upload. A failed caption is logged; it does not fail the upload.
+func uploadYouTubeAPI(ctx context.Context, jctx JobContext, filename string, video youtube.Video, meta map[string]any) (string, error) {
+	client, err := NewYoutubeUploader(jctx.Config)
+	if err != nil {
+		return "", err
+	}
+	result, err := client.UploadVideo(ctx, filename, video)
+	if err != nil {
+		return "", err
+	}
 
-	return jctx.Store.UpdateContentMetaStatus(ctx, content.ID, j.QueueOutput, meta)
+	now := time.Now().Format(time.RFC3339)
+	youtubeMeta, _ := meta["youtube"].(map[string]any)
+	if youtubeMeta == nil {
+		youtubeMeta = map[string]any{}
+	}
+	youtubeMeta["upload"] = map[string]any{
+		"uploader":      "api",
+		"upload_status": result.UploadStatus,
+		"bytes":         result.Bytes,
+		"chunks":        result.Chunks,
+		"retries":       result.Retries,
+		"duration_s":    math.Round(result.Duration.Seconds()*10) / 10,
+		"uploaded_at":   now,
+	}
+	meta["youtube"] = youtubeMeta
+
+	tracks, _ := utils.GetMap(meta, "subtitles", "tracks")
+	langs := make([]string, 0, len(tracks))
+	for lang := range tracks {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		track, _ := tracks[lang].(map[string]any)
+		srt, _ := track["srt"].(string)
+		if strings.TrimSpace(srt) == "" {
+			continue
+		}
+		captionID, err := client.InsertCaption(ctx, result.VideoID, lang, "", []byte(srt))
+		if err != nil {
+			utils.Warn("UploadYouTube caption track not uploaded", "lang", lang, "video_id", result.VideoID, "err", err)
+			continue
+		}
+		track["youtube_caption_id"] = captionID
+		track["uploaded_at"] = now
+	}
+	return result.VideoID, nil
+}
+
+// writeCaptionTracks writes meta.subtitles.tracks[lang].srt to disk and returns the
+// " --caption=lang:path" arguments for the upload script (empty when there are no tracks).
+func writeCaptionTracks(jctx JobContext, contentID int64, meta map[string]any) (string, error) {
+	tracks, ok := utils.GetMap(meta, "subtitles", "tracks")
+	if !ok || len(tracks) == 0 {
+		return "", nil
+	}
+	dir := filepath.Join(jctx.Config.SubtitleFolder, "tracks")
+	if err := utils.EnsureDir(dir); err != nil {
+		return "", err
+	}
+	langs := make([]string, 0, len(tracks))
+	for lang := range tracks {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	var args strings.Builder
+	for _, lang := range langs {
+		track, _ := tracks[lang].(map[string]any)
+		srt, _ := track["srt"].(string)
+		if strings.TrimSpace(srt) == "" {
+			continue
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%010d.%s.srt", contentID, lang))
+		if err := os.WriteFile(path, []byte(srt), 0o644); err != nil {
+			return "", err
+		}
+		args.WriteString(" --caption=")
+		args.WriteString(utils.ShellEscape(lang + ":" + path))
+	}
+	return args.String(), nil
+}
+
+var captionUploadedRegex = regexp.MustCompile(`Caption id '([^']+)' \(([^)]+)\) was successfully uploaded`)
+
+// recordUploadedCaptions stores the YouTube caption ids reported by the upload script on each track.
+func recordUploadedCaptions(meta map[string]any, output string) {
+	tracks, ok := utils.GetMap(meta, "subtitles", "tracks")
+	if !ok {
+		return
+	}
+	for _, m := range captionUploadedRegex.FindAllStringSubmatch(output, -1) {
+		track, ok := tracks[m[2]].(map[string]any)
+		if !ok {
+			continue
+		}
+		track["youtube_caption_id"] = m[1]
+		track["uploaded_at"] = time.Now().Format(time.RFC3339)
+	}
+	for lang, raw := range tracks {
+		track, _ := raw.(map[string]any)
+		if track != nil && track["srt"] != nil && track["youtube_caption_id"] == nil {
+			utils.Warn("UploadYouTube caption track not uploaded", "lang", lang)
+		}
+	}
 }
 
 func extractYouTubeID(input string) string {
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"ai-things/manager-go/internal/config"
+	"ai-things/manager-go/internal/youtube"
+)
+
+// NewYoutubeUploader returns a YouTube client authorized with the OAuth client and refresh token
+// configured in [youtube]. Refreshed tokens are written back to the token file.
+func NewYoutubeUploader(cfg config.Config) (*youtube.Client, error) {
+	if cfg.YoutubeClientSecretsFile == "" || cfg.YoutubeTokenFile == "" {
+		return nil, errors.New("youtube.client_secrets_file and youtube.token_file are required for uploader=api")
+	}
+	oauth, err := youtube.LoadClientSecrets(cfg.YoutubeClientSecretsFile)
+	if err != nil {
+		return nil, err
+	}
+	token, err := youtube.LoadToken(cfg.YoutubeTokenFile)
+	if err != nil {
+		return nil, err
+	}
+	tokens := youtube.NewTokenSource(oauth, token)
+	tokens.HTTP = &http.Client{Timeout: 30 * time.Second}
+	tokens.Save = func(token youtube.Token) error {
+		return youtube.SaveToken(cfg.YoutubeTokenFile, token)
+	}
+
+	client := youtube.NewClient(cfg.YoutubeAPIKey)
+	// No overall client timeout: a chunk on a slow link can take minutes, so each upload request
+	// gets its own deadline (youtube.Client.RequestTimeout) and the transport fails stalled
+	// connections early.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = 2 * time.Minute
+	transport.IdleConnTimeout = 90 * time.Second
+	client.HTTP = &http.Client{Transport: transport}
+	client.Tokens = tokens
+	client.ChunkSize = int64(cfg.YoutubeUploadChunkMB) << 20
+	client.MaxRetries = cfg.YoutubeUploadRetries
+	return client, nil
+}
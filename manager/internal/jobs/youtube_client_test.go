@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ai-things/manager-go/internal/config"
+	"ai-things/manager-go/internal/youtube"
+)
+
+func TestNewYoutubeUploader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("refresh_token") != "refresh" || r.PostForm.Get("client_id") != "id" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"access","expires_in":3600}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfg := config.Config{
+		YoutubeClientSecretsFile: filepath.Join(dir, "client_secrets.json"),
+		YoutubeTokenFile:         filepath.Join(dir, "token.json"),
+		YoutubeUploadChunkMB:     1,
+		YoutubeUploadRetries:     4,
+	}
+	secrets := fmt.Sprintf(`{"installed":{"client_id":"id","client_secret":"secret","token_uri":%q}}`, srv.URL)
+	if err := os.WriteFile(cfg.YoutubeClientSecretsFile, []byte(secrets), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := youtube.SaveToken(cfg.YoutubeTokenFile, youtube.Token{RefreshToken: "refresh"}); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewYoutubeUploader(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.ChunkSize != 1<<20 || client.MaxRetries != 4 || client.UploadBaseURL != youtube.DefaultUploadBaseURL {
+		t.Errorf("client = %+v", client)
+	}
+	if client.HTTP == nil || client.HTTP.Transport == nil {
+		t.Error("uploads need a transport with timeouts")
+	}
+	if token, err := client.Tokens.AccessToken(context.Background()); err != nil || token != "access" {
+		t.Fatalf("AccessToken = %q, %v", token, err)
+	}
+	// The refreshed access token is written back next to the refresh token.
+	saved, err := youtube.LoadToken(cfg.YoutubeTokenFile)
+	if err != nil || saved.AccessToken != "access" || saved.RefreshToken != "refresh" {
+		t.Errorf("saved token = %+v, %v", saved, err)
+	}
+}
+
+func TestNewYoutubeUploaderRequiresFiles(t *testing.T) {
+	if _, err := NewYoutubeUploader(config.Config{}); err == nil {
+		t.Fatal("want an error without client_secrets_file and token_file")
+	}
+	cfg := config.Config{YoutubeClientSecretsFile: filepath.Join(t.TempDir(), "missing.json"), YoutubeTokenFile: "token.json"}
+	if _, err := NewYoutubeUploader(cfg); err == nil {
+		t.Fatal("want an error for a missing secrets file")
+	}
+}
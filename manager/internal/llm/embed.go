@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"ai-things/manager-go/internal/utils"
+)
+
+// Embedder turns texts into vectors for similarity search.
+type Embedder interface {
+	Name() string
+	Model() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewEmbedder returns the embedding provider named by spec (see New). Only Ollama-compatible
+// providers support embeddings.
+func NewEmbedder(spec string, s Settings) (Embedder, error) {
+	provider, err := New(spec, s)
+	if err != nil {
+		return nil, err
+	}
+	retry := provider.(Retry)
+	if _, ok := retry.Provider.(Embedder); !ok {
+		return nil, fmt.Errorf("llm provider %s does not support embeddings", provider.Name())
+	}
+	return retry, nil
+}
+
+// Embed retries temporary failures like Generate.
+func (r Retry) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	embedder, ok := r.Provider.(Embedder)
+	if !ok {
+		return nil, fmt.Errorf("llm provider %s does not support embeddings", r.Name())
+	}
+	var vectors [][]float32
+	err := r.do(ctx, r.Model(), func() error {
+		var err error
+		vectors, err = embedder.Embed(ctx, texts)
+		return err
+	})
+	return vectors, err
+}
+
+// Embed calls the /api/embed endpoint, one vector per text.
+func (o Ollama) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(map[string]any{
+		"model":      o.DefaultModel,
+		"keep_alive": 300,
+		"input":      texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimRight(o.URL, "/") + "/api/embed"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		httpReq.Header.Set("X-API-key", o.APIKey)
+	}
+
+	utils.Debug("llm embed", "provider", o.ProviderName, "url", url, "model", o.DefaultModel, "texts", len(texts))
+	resp, err := (&http.Client{Timeout: firstNonZero(o.Timeout, defaultTimeout)}).Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{Provider: o.ProviderName, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	var decoded struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+	if len(decoded.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("%s embed: got %d vectors for %d texts", o.ProviderName, len(decoded.Embeddings), len(texts))
+	}
+	return decoded.Embeddings, nil
+}
+
+// Cosine returns the cosine similarity of two vectors (0 when their lengths differ or one is zero).
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,127 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/utils"
+)
+
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta/models"
+
+// Gemini calls the generateContent endpoint of the Gemini API.
+type Gemini struct {
+	APIKey       string
+	DefaultModel string
+	Timeout      time.Duration
+}
+
+func (g Gemini) Name() string  { return "gemini" }
+func (g Gemini) Model() string { return g.DefaultModel }
+
+func (g Gemini) Generate(ctx context.Context, req Request) (Response, error) {
+	model := firstNonEmpty(req.Model, g.DefaultModel)
+	payload := map[string]any{
+		"contents": []any{
+			map[string]any{
+				"role":  "user",
+				"parts": []any{map[string]any{"text": req.User}},
+			},
+		},
+	}
+	if req.System != "" {
+		payload["systemInstruction"] = map[string]any{
+			"parts": []any{map[string]any{"text": req.System}},
+		}
+	}
+	generation := map[string]any{}
+	if req.JSON {
+		generation["responseMimeType"] = "application/json"
+	}
+	if req.Temperature != nil {
+		generation["temperature"] = *req.Temperature
+	}
+	if req.Seed != nil {
+		generation["seed"] = *req.Seed
+	}
+	if len(generation) > 0 {
+		payload["generationConfig"] = generation
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Response{}, err
+	}
+
+	endpoint := fmt.Sprintf("%s/%s:generateContent?key=%s", geminiBaseURL, url.PathEscape(model), url.QueryEscape(g.APIKey))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	utils.Debug("llm generate", "provider", "gemini", "model", model, "json", req.JSON, "prompt_len", len(req.User))
+	resp, err := (&http.Client{Timeout: firstNonZero(req.Timeout, g.Timeout, defaultTimeout)}).Do(httpReq)
+	if err != nil {
+		// The API key travels in the query string; keep it out of logged errors.
+		return Response{}, redactKey(err, g.APIKey)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return Response{}, &StatusError{Provider: "gemini", StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	var decoded struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+			FinishReason string `json:"finishReason"`
+		} `json:"candidates"`
+		PromptFeedback struct {
+			BlockReason string `json:"blockReason"`
+		} `json:"promptFeedback"`
+		ModelVersion string `json:"modelVersion"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return Response{}, err
+	}
+	if decoded.PromptFeedback.BlockReason != "" {
+		return Response{}, fmt.Errorf("gemini blocked prompt: %s", decoded.PromptFeedback.BlockReason)
+	}
+	if len(decoded.Candidates) == 0 {
+		return Response{}, ErrEmptyResponse
+	}
+
+	var text strings.Builder
+	for _, part := range decoded.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	utils.Debug("llm response", "provider", "gemini", "model", model, "response_len", text.Len(), "finish_reason", decoded.Candidates[0].FinishReason)
+	if strings.TrimSpace(text.String()) == "" {
+		if reason := decoded.Candidates[0].FinishReason; reason != "" && reason != "STOP" {
+			return Response{}, fmt.Errorf("gemini returned no text (finish reason %s)", reason)
+		}
+		return Response{}, ErrEmptyResponse
+	}
+	return Response{Text: text.String(), Provider: "gemini", Model: firstNonEmpty(decoded.ModelVersion, model)}, nil
+}
+
+func redactKey(err error, key string) error {
+	if key == "" || !strings.Contains(err.Error(), key) {
+		return err
+	}
+	if urlErr, ok := err.(*url.Error); ok {
+		return &url.Error{Op: urlErr.Op, URL: strings.ReplaceAll(urlErr.URL, url.QueryEscape(key), "REDACTED"), Err: urlErr.Err}
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"ai-things/manager-go/internal/utils"
+)
+
+const repairPrompt = `Your previous answer could not be used: %s
+
+Previous answer:
+%s
+
+Answer again with the corrected JSON object only, following the format described below.
+
+%s`
+
+// GenerateJSON runs req in JSON mode and hands the answer to decode, which parses and validates
+// it. When decode fails, the model is shown the error and its previous answer and asked again,
+// up to repairs times.
+func GenerateJSON(ctx context.Context, provider Provider, req Request, repairs int, decode func(text string) error) (Response, error) {
+	req.JSON = true
+	original := req.User
+
+	var lastErr error
+	for attempt := 0; attempt <= repairs; attempt++ {
+		response, err := provider.Generate(ctx, req)
+		if err != nil {
+			return Response{}, err
+		}
+		if lastErr = decode(response.Text); lastErr == nil {
+			return response, nil
+		}
+
+		utils.Warn("llm JSON response invalid",
+			"provider", response.Provider,
+			"model", response.Model,
+			"attempt", attempt+1,
+			"error", lastErr,
+		)
+		req.User = fmt.Sprintf(repairPrompt, lastErr, response.Text, original)
+	}
+	return Response{}, fmt.Errorf("llm JSON response invalid after %d attempts: %w", repairs+1, lastErr)
+}
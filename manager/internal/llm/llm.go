@@ -0,0 +1,223 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/utils"
+)
+
+const defaultTimeout = 600 * time.Second
+
+// Request is a single prompt/completion call.
+type Request struct {
+	// Model overrides the provider's default model.
+	Model  string
+	System string
+	User   string
+	// JSON asks the model to answer with a JSON document only.
+	JSON bool
+	// Temperature and Seed are sent only when set.
+	Temperature *float64
+	Seed        *int64
+	// Timeout bounds each attempt; zero uses the provider default.
+	Timeout time.Duration
+}
+
+// Response is the generated text plus what produced it.
+type Response struct {
+	Text     string `json:"text"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+}
+
+// Provider generates text from a prompt.
+type Provider interface {
+	Name() string
+	Model() string
+	Generate(ctx context.Context, req Request) (Response, error)
+}
+
+// StatusError is returned when the provider answers with a non-2xx status.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s response status %d", e.Provider, e.StatusCode)
+	}
+	return fmt.Sprintf("%s response status=%d body=%s", e.Provider, e.StatusCode, e.Body)
+}
+
+// Temporary reports whether the request may succeed if retried (rate limits, timeouts, server errors).
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == 408 || e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// ErrEmptyResponse is returned when the provider answered without any text.
+var ErrEmptyResponse = errors.New("empty llm response")
+
+// Float and Int64 build the optional Request fields.
+func Float(v float64) *float64 { return &v }
+func Int64(v int64) *int64     { return &v }
+
+// Retry wraps a provider and retries temporary failures with a linear backoff.
+type Retry struct {
+	Provider
+	Attempts int
+	Delay    time.Duration
+}
+
+func (r Retry) Generate(ctx context.Context, req Request) (Response, error) {
+	var resp Response
+	err := r.do(ctx, firstNonEmpty(req.Model, r.Model()), func() error {
+		var err error
+		resp, err = r.Provider.Generate(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// do runs call until it succeeds, fails with a permanent error or runs out of attempts.
+func (r Retry) do(ctx context.Context, model string, call func() error) error {
+	attempts := max(r.Attempts, 1)
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err := call()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if attempt == attempts || !retryable(err) || ctx.Err() != nil {
+			break
+		}
+		wait := r.Delay * time.Duration(attempt)
+		utils.Warn("llm request failed; retrying",
+			"provider", r.Name(),
+			"model", model,
+			"attempt", attempt,
+			"wait_s", wait.Seconds(),
+			"error", err,
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return lastErr
+}
+
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	// Transport failures (connection refused, client timeout) surface as *url.Error.
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || errors.Is(err, ErrEmptyResponse)
+}
+
+// Settings holds the per-provider connection details.
+type Settings struct {
+	OllamaURL   string
+	OllamaModel string
+
+	GeminiAPIKey string
+	GeminiModel  string
+
+	Portnumber53URL     string
+	Portnumber53APIKey  string
+	Portnumber53Model   string
+	Portnumber53Timeout time.Duration
+
+	// Timeout is the default per-attempt timeout for ollama and gemini.
+	Timeout    time.Duration
+	Retries    int
+	RetryDelay time.Duration
+}
+
+// ParseSpec splits a "provider:model" spec. Only the first colon separates the two, so ollama
+// tags such as "ollama:llama3.1:8b" work; the model may be empty to use the provider default.
+func ParseSpec(spec string) (string, string) {
+	provider, model, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	return strings.ToLower(strings.TrimSpace(provider)), strings.TrimSpace(model)
+}
+
+// New returns the provider named by spec ("ollama", "gemini" or "portnumber53", optionally
+// followed by ":model"), wrapped with retries.
+func New(spec string, s Settings) (Provider, error) {
+	name, model := ParseSpec(spec)
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	var provider Provider
+	switch name {
+	case "ollama":
+		if strings.TrimSpace(s.OllamaURL) == "" {
+			return nil, errors.New("llm provider ollama: missing url (set ollama.hostname in config.ini)")
+		}
+		provider = Ollama{
+			ProviderName: "ollama",
+			URL:          s.OllamaURL,
+			DefaultModel: firstNonEmpty(model, s.OllamaModel, "llama3.2"),
+			Timeout:      timeout,
+		}
+	case "portnumber53":
+		if s.Portnumber53APIKey == "" {
+			return nil, errors.New("llm provider portnumber53: missing api key (set portnumber53.api_key in config.ini)")
+		}
+		provider = Ollama{
+			ProviderName: "portnumber53",
+			URL:          firstNonEmpty(s.Portnumber53URL, "https://ollama.portnumber53.com"),
+			APIKey:       s.Portnumber53APIKey,
+			DefaultModel: firstNonEmpty(model, s.Portnumber53Model, "llama3.3"),
+			Timeout:      firstNonZero(s.Portnumber53Timeout, timeout),
+		}
+	case "gemini":
+		if s.GeminiAPIKey == "" {
+			return nil, errors.New("llm provider gemini: missing api key (set gemini.api_key in config.ini)")
+		}
+		provider = Gemini{
+			APIKey:       s.GeminiAPIKey,
+			DefaultModel: firstNonEmpty(model, s.GeminiModel, "gemini-1.5-flash"),
+			Timeout:      timeout,
+		}
+	case "":
+		return nil, errors.New("llm provider not configured")
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q", name)
+	}
+
+	return Retry{Provider: provider, Attempts: s.Retries + 1, Delay: s.RetryDelay}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func firstNonZero(values ...time.Duration) time.Duration {
+	for _, value := range values {
+		if value > 0 {
+			return value
+		}
+	}
+	return 0
+}
@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/utils"
+)
+
+// Ollama calls the /api/generate endpoint of an Ollama server. The portnumber53 gateway speaks
+// the same API behind an X-API-key header, so it is an Ollama with an APIKey.
+type Ollama struct {
+	ProviderName string
+	// URL is the server base URL, e.g. http://localhost:11434.
+	URL          string
+	APIKey       string
+	DefaultModel string
+	Timeout      time.Duration
+}
+
+func (o Ollama) Name() string  { return o.ProviderName }
+func (o Ollama) Model() string { return o.DefaultModel }
+
+func (o Ollama) Generate(ctx context.Context, req Request) (Response, error) {
+	model := firstNonEmpty(req.Model, o.DefaultModel)
+	payload := map[string]any{
+		"model":      model,
+		"keep_alive": 300,
+		"prompt":     req.User,
+		"stream":     false,
+	}
+	if req.System != "" {
+		payload["system"] = req.System
+	}
+	if req.JSON {
+		payload["format"] = "json"
+	}
+	options := map[string]any{}
+	if req.Temperature != nil {
+		options["temperature"] = *req.Temperature
+	}
+	if req.Seed != nil {
+		options["seed"] = *req.Seed
+	}
+	if len(options) > 0 {
+		payload["options"] = options
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Response{}, err
+	}
+
+	url := strings.TrimRight(o.URL, "/") + "/api/generate"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		httpReq.Header.Set("X-API-key", o.APIKey)
+	}
+
+	utils.Debug("llm generate", "provider", o.ProviderName, "url", url, "model", model, "json", req.JSON, "prompt_len", len(req.User))
+	resp, err := (&http.Client{Timeout: firstNonZero(req.Timeout, o.Timeout, defaultTimeout)}).Do(httpReq)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return Response{}, &StatusError{Provider: o.ProviderName, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	}
+
+	var decoded struct {
+		Model    string `json:"model"`
+		Response string `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return Response{}, err
+	}
+	utils.Debug("llm response", "provider", o.ProviderName, "model", model, "response_len", len(decoded.Response))
+	if strings.TrimSpace(decoded.Response) == "" {
+		return Response{}, ErrEmptyResponse
+	}
+	return Response{Text: decoded.Response, Provider: o.ProviderName, Model: firstNonEmpty(decoded.Model, model)}, nil
+}
@@ -0,0 +1,152 @@
+package music
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LibraryFile is the index file expected at the root of the music library folder.
+const LibraryFile = "library.json"
+
+// Track is a single background music file plus the attribution needed to credit it.
+type Track struct {
+	File      string   `json:"file"`
+	Title     string   `json:"title"`
+	Artist    string   `json:"artist"`
+	License   string   `json:"license"`
+	SourceURL string   `json:"source_url"`
+	Moods     []string `json:"moods"`
+	Subjects  []string `json:"subjects"`
+	// Gain overrides the configured bed volume for tracks that were mastered louder/quieter.
+	Gain float64 `json:"gain"`
+}
+
+// Rule maps a subject/title keyword to a mood.
+// The first rule whose keyword appears in the content subject (or title) wins.
+type Rule struct {
+	Match string `json:"match"`
+	Mood  string `json:"mood"`
+}
+
+// Library is the parsed library.json plus the folder it was loaded from.
+//
+// Example library.json:
+//
+//	{
+//	  "default_mood": "calm",
+//	  "rules": [{"match": "space", "mood": "ambient"}, {"match": "war", "mood": "dramatic"}],
+//	  "tracks": [{"file": "deep-field.mp3", "title": "Deep Field", "artist": "Someone",
+//	              "license": "CC BY 4.0", "source_url": "https://...", "moods": ["ambient"]}]
+//	}
+type Library struct {
+	Dir         string  `json:"-"`
+	DefaultMood string  `json:"default_mood"`
+	Rules       []Rule  `json:"rules"`
+	Tracks      []Track `json:"tracks"`
+}
+
+// LoadLibrary reads <dir>/library.json and drops tracks whose file is missing on disk.
+func LoadLibrary(dir string) (Library, error) {
+	if strings.TrimSpace(dir) == "" {
+		return Library{}, errors.New("music library folder not configured")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, LibraryFile))
+	if err != nil {
+		return Library{}, err
+	}
+	var lib Library
+	if err := json.Unmarshal(data, &lib); err != nil {
+		return Library{}, fmt.Errorf("parse %s: %w", LibraryFile, err)
+	}
+	lib.Dir = dir
+
+	tracks := make([]Track, 0, len(lib.Tracks))
+	for _, t := range lib.Tracks {
+		if strings.TrimSpace(t.File) == "" {
+			continue
+		}
+		if st, err := os.Stat(lib.Path(t)); err != nil || st.IsDir() {
+			continue
+		}
+		tracks = append(tracks, t)
+	}
+	lib.Tracks = tracks
+	return lib, nil
+}
+
+// Path returns the absolute path of a track inside the library.
+func (l Library) Path(t Track) string {
+	if filepath.IsAbs(t.File) {
+		return t.File
+	}
+	return filepath.Join(l.Dir, t.File)
+}
+
+// MoodFor resolves the mood for a subject/title using the library rules.
+func (l Library) MoodFor(subject, title string) string {
+	haystacks := []string{strings.ToLower(subject), strings.ToLower(title)}
+	for _, hay := range haystacks {
+		if hay == "" {
+			continue
+		}
+		for _, r := range l.Rules {
+			match := strings.ToLower(strings.TrimSpace(r.Match))
+			if match != "" && strings.Contains(hay, match) {
+				return r.Mood
+			}
+		}
+	}
+	return l.DefaultMood
+}
+
+// Select picks a track for the given subject/title.
+// Preference order: tracks tagged with the subject, tracks tagged with the resolved mood,
+// tracks tagged with the default mood, then any track. The seed (usually the content ID)
+// keeps the choice stable across re-renders.
+func (l Library) Select(subject, title string, seed int64) (Track, string, bool) {
+	if len(l.Tracks) == 0 {
+		return Track{}, "", false
+	}
+	mood := l.MoodFor(subject, title)
+
+	pools := [][]Track{
+		l.filter(func(t Track) bool { return subject != "" && hasTag(t.Subjects, subject) }),
+		l.filter(func(t Track) bool { return mood != "" && hasTag(t.Moods, mood) }),
+		l.filter(func(t Track) bool { return l.DefaultMood != "" && hasTag(t.Moods, l.DefaultMood) }),
+		l.Tracks,
+	}
+	for _, pool := range pools {
+		if len(pool) == 0 {
+			continue
+		}
+		if seed < 0 {
+			seed = -seed
+		}
+		return pool[seed%int64(len(pool))], mood, true
+	}
+	return Track{}, mood, false
+}
+
+func (l Library) filter(keep func(Track) bool) []Track {
+	var out []Track
+	for _, t := range l.Tracks {
+		if keep(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func hasTag(tags []string, want string) bool {
+	want = strings.ToLower(strings.TrimSpace(want))
+	for _, tag := range tags {
+		if strings.ToLower(strings.TrimSpace(tag)) == want {
+			return true
+		}
+	}
+	return false
+}
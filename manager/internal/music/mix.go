@@ -0,0 +1,72 @@
+package music
+
+import (
+	"fmt"
+	"strconv"
+
+	"ai-things/manager-go/internal/utils"
+)
+
+// MixOptions controls how the bed is laid under the narration.
+type MixOptions struct {
+	// Duration of the narration in seconds (meta.mp3s[0].duration). The mix is cut to this length.
+	Duration float64
+	// Volume is the bed gain before ducking (0..1).
+	Volume float64
+	// FadeSeconds is used for both the fade-in at the start and the fade-out at the end.
+	FadeSeconds float64
+}
+
+func (o MixOptions) withDefaults() MixOptions {
+	if o.Volume <= 0 {
+		o.Volume = 0.15
+	}
+	if o.FadeSeconds <= 0 {
+		o.FadeSeconds = 3
+	}
+	// Never fade for longer than half the episode, or the fades would overlap.
+	if o.Duration > 0 && o.FadeSeconds > o.Duration/2 {
+		o.FadeSeconds = o.Duration / 2
+	}
+	return o
+}
+
+// MixCommand builds the ffmpeg command that loops the bed for the whole episode, fades it in/out,
+// ducks it under the voice using sidechain compression and writes an mp3 to outPath. Without a
+// known duration the bed is mixed without the trim and fades; amix still ends with the voice.
+func MixCommand(voicePath, bedPath, outPath string, opts MixOptions) (string, error) {
+	opts = opts.withDefaults()
+
+	bed := fmt.Sprintf("[1:a]volume=%s[bed];", formatSeconds(opts.Volume))
+	limit := ""
+	if opts.Duration > 0 {
+		duration := formatSeconds(opts.Duration)
+		fade := formatSeconds(opts.FadeSeconds)
+		bed = fmt.Sprintf(
+			"[1:a]atrim=0:%s,asetpts=PTS-STARTPTS,volume=%s,afade=t=in:st=0:d=%s,afade=t=out:st=%s:d=%s[bed];",
+			duration,
+			formatSeconds(opts.Volume),
+			fade,
+			formatSeconds(opts.Duration-opts.FadeSeconds),
+			fade,
+		)
+		limit = " -t " + duration
+	}
+	filter := "[0:a]asplit=2[voice][key];" +
+		bed +
+		"[bed][key]sidechaincompress=threshold=0.02:ratio=8:attack=20:release=400[ducked];" +
+		"[voice][ducked]amix=inputs=2:duration=first:dropout_transition=0:normalize=0[out]"
+
+	return fmt.Sprintf(
+		"ffmpeg -y -i %s -stream_loop -1 -i %s -filter_complex %s -map '[out]'%s -acodec libmp3lame %s",
+		utils.ShellEscape(voicePath),
+		utils.ShellEscape(bedPath),
+		utils.ShellEscape(filter),
+		limit,
+		utils.ShellEscape(outPath),
+	), nil
+}
+
+func formatSeconds(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
@@ -0,0 +1,211 @@
+package podcastfeed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const itunesNS = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+
+// Channel describes the show itself (the <channel> element).
+type Channel struct {
+	Title       string
+	Link        string
+	FeedURL     string
+	Description string
+	Language    string
+	Author      string
+	OwnerName   string
+	OwnerEmail  string
+	ImageURL    string
+	Category    string
+	Explicit    bool
+}
+
+// Episode is a single <item>.
+type Episode struct {
+	GUID        string
+	Title       string
+	Description string
+	Link        string
+	AudioURL    string
+	AudioType   string
+	AudioLength int64
+	Duration    time.Duration
+	ImageURL    string
+	PublishedAt time.Time
+}
+
+// Render serializes the channel and episodes as an RSS 2.0 document with the iTunes namespace.
+func Render(ch Channel, episodes []Episode) ([]byte, error) {
+	doc := rssDoc{
+		Version:  "2.0",
+		ItunesNS: itunesNS,
+		Channel: rssChannel{
+			Title:          ch.Title,
+			Link:           ch.Link,
+			Description:    ch.Description,
+			Language:       ch.Language,
+			Generator:      "ai-things manager",
+			LastBuildDate:  time.Now().UTC().Format(time.RFC1123Z),
+			ItunesAuthor:   ch.Author,
+			ItunesSummary:  ch.Description,
+			ItunesExplicit: explicitValue(ch.Explicit),
+			ItunesType:     "episodic",
+		},
+	}
+	if ch.FeedURL != "" {
+		doc.AtomNS = "http://www.w3.org/2005/Atom"
+		doc.Channel.AtomLink = &atomLink{Href: ch.FeedURL, Rel: "self", Type: "application/rss+xml"}
+	}
+	if ch.ImageURL != "" {
+		doc.Channel.ItunesImage = &itunesImage{Href: ch.ImageURL}
+		doc.Channel.Image = &rssImage{URL: ch.ImageURL, Title: ch.Title, Link: ch.Link}
+	}
+	if ch.Category != "" {
+		doc.Channel.ItunesCategory = &itunesCategory{Text: ch.Category}
+	}
+	if ch.OwnerName != "" || ch.OwnerEmail != "" {
+		doc.Channel.ItunesOwner = &itunesOwner{Name: ch.OwnerName, Email: ch.OwnerEmail}
+	}
+
+	for _, ep := range episodes {
+		audioType := ep.AudioType
+		if audioType == "" {
+			audioType = "audio/mpeg"
+		}
+		item := rssItem{
+			Title:          ep.Title,
+			Description:    cdata{Text: ep.Description},
+			Link:           ep.Link,
+			GUID:           rssGUID{Value: ep.GUID, IsPermaLink: "false"},
+			PubDate:        ep.PublishedAt.UTC().Format(time.RFC1123Z),
+			Enclosure:      rssEnclosure{URL: ep.AudioURL, Length: ep.AudioLength, Type: audioType},
+			ItunesTitle:    ep.Title,
+			ItunesDuration: formatDuration(ep.Duration),
+			ItunesExplicit: explicitValue(ch.Explicit),
+			ItunesType:     "full",
+		}
+		if ep.ImageURL != "" {
+			item.ItunesImage = &itunesImage{Href: ep.ImageURL}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	total := int64(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, (total%3600)/60, total%60)
+}
+
+func explicitValue(explicit bool) string {
+	if explicit {
+		return "true"
+	}
+	return "false"
+}
+
+type rssDoc struct {
+	XMLName  xml.Name   `xml:"rss"`
+	Version  string     `xml:"version,attr"`
+	ItunesNS string     `xml:"xmlns:itunes,attr"`
+	AtomNS   string     `xml:"xmlns:atom,attr,omitempty"`
+	Channel  rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title          string          `xml:"title"`
+	Link           string          `xml:"link"`
+	Description    string          `xml:"description"`
+	Language       string          `xml:"language,omitempty"`
+	Generator      string          `xml:"generator"`
+	LastBuildDate  string          `xml:"lastBuildDate"`
+	AtomLink       *atomLink       `xml:"atom:link,omitempty"`
+	Image          *rssImage       `xml:"image,omitempty"`
+	ItunesAuthor   string          `xml:"itunes:author,omitempty"`
+	ItunesSummary  string          `xml:"itunes:summary,omitempty"`
+	ItunesExplicit string          `xml:"itunes:explicit"`
+	ItunesType     string          `xml:"itunes:type"`
+	ItunesImage    *itunesImage    `xml:"itunes:image,omitempty"`
+	ItunesCategory *itunesCategory `xml:"itunes:category,omitempty"`
+	ItunesOwner    *itunesOwner    `xml:"itunes:owner,omitempty"`
+	Items          []rssItem       `xml:"item"`
+}
+
+type rssItem struct {
+	Title          string       `xml:"title"`
+	Description    cdata        `xml:"description"`
+	Link           string       `xml:"link,omitempty"`
+	GUID           rssGUID      `xml:"guid"`
+	PubDate        string       `xml:"pubDate"`
+	Enclosure      rssEnclosure `xml:"enclosure"`
+	ItunesTitle    string       `xml:"itunes:title"`
+	ItunesDuration string       `xml:"itunes:duration,omitempty"`
+	ItunesExplicit string       `xml:"itunes:explicit"`
+	ItunesType     string       `xml:"itunes:episodeType"`
+	ItunesImage    *itunesImage `xml:"itunes:image,omitempty"`
+}
+
+type cdata struct {
+	Text string `xml:",cdata"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssImage struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type itunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type itunesCategory struct {
+	Text string `xml:"text,attr"`
+}
+
+type itunesOwner struct {
+	Name  string `xml:"itunes:name,omitempty"`
+	Email string `xml:"itunes:email,omitempty"`
+}
+
+// CleanText collapses whitespace so descriptions render well in podcast apps.
+func CleanText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n\n")
+}
@@ -0,0 +1,178 @@
+package prompts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Templates live in <dir>/<name>/v<version>.tmpl. The file body renders the user prompt; an
+// optional {{define "system"}}...{{end}} block renders the system prompt.
+var versionFileRegex = regexp.MustCompile(`^v(\d+)\.tmpl$`)
+
+// Vars are the variables available to templates. Templates supply their own defaults with
+// {{or .Length "6 to 10"}}.
+type Vars struct {
+	Subject  string
+	Length   string
+	Language string
+	Tone     string
+	// Text is the input document (article, narration) for prompts that work on existing text.
+	Text string
+	// Avoid lists titles of existing content the answer must not repeat.
+	Avoid []string
+	// Choices lists existing names (e.g. subjects) the answer should pick from when one fits.
+	Choices []string
+}
+
+// Template is one version of a named prompt on disk.
+type Template struct {
+	Name    string
+	Version int
+	Path    string
+}
+
+// Prompt is a rendered template.
+type Prompt struct {
+	Template
+	System string
+	User   string
+	// SHA256 of the template source, so edits without a version bump are still detectable.
+	SHA256 string
+}
+
+// Library resolves templates from a folder. Pins select a version per name; unpinned names use
+// the highest version available.
+type Library struct {
+	Dir  string
+	Pins map[string]int
+}
+
+// List returns every template version, sorted by name then version.
+func (l Library) List() ([]Template, error) {
+	entries, err := os.ReadDir(l.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("prompt folder: %w", err)
+	}
+	var out []Template
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		versions, err := l.versions(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, versions...)
+	}
+	sort.Slice(out, func(i, k int) bool {
+		if out[i].Name != out[k].Name {
+			return out[i].Name < out[k].Name
+		}
+		return out[i].Version < out[k].Version
+	})
+	return out, nil
+}
+
+func (l Library) versions(name string) ([]Template, error) {
+	entries, err := os.ReadDir(filepath.Join(l.Dir, name))
+	if err != nil {
+		return nil, err
+	}
+	var out []Template
+	for _, entry := range entries {
+		m := versionFileRegex.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		out = append(out, Template{Name: name, Version: version, Path: filepath.Join(l.Dir, name, entry.Name())})
+	}
+	return out, nil
+}
+
+// Lookup finds a template. Version 0 means the pinned version, or the latest one.
+func (l Library) Lookup(name string, version int) (Template, error) {
+	if version == 0 {
+		version = l.Pins[name]
+	}
+	versions, err := l.versions(name)
+	if err != nil || len(versions) == 0 {
+		return Template{}, fmt.Errorf("prompt template %q not found in %s", name, l.Dir)
+	}
+	best := Template{}
+	for _, t := range versions {
+		if version > 0 && t.Version == version {
+			return t, nil
+		}
+		if t.Version > best.Version {
+			best = t
+		}
+	}
+	if version > 0 {
+		return Template{}, fmt.Errorf("prompt template %s version %d not found", name, version)
+	}
+	return best, nil
+}
+
+// Render looks up a template and renders it.
+func (l Library) Render(name string, version int, vars Vars) (Prompt, error) {
+	t, err := l.Lookup(name, version)
+	if err != nil {
+		return Prompt{}, err
+	}
+	return t.Render(vars)
+}
+
+// Render executes the template with vars.
+func (t Template) Render(vars Vars) (Prompt, error) {
+	source, err := os.ReadFile(t.Path)
+	if err != nil {
+		return Prompt{}, err
+	}
+	tmpl, err := template.New(t.Name).Parse(string(source))
+	if err != nil {
+		return Prompt{}, fmt.Errorf("parse prompt %s v%d: %w", t.Name, t.Version, err)
+	}
+
+	var user bytes.Buffer
+	if err := tmpl.Execute(&user, vars); err != nil {
+		return Prompt{}, fmt.Errorf("render prompt %s v%d: %w", t.Name, t.Version, err)
+	}
+	var system bytes.Buffer
+	if tmpl.Lookup("system") != nil {
+		if err := tmpl.ExecuteTemplate(&system, "system", vars); err != nil {
+			return Prompt{}, fmt.Errorf("render prompt %s v%d system: %w", t.Name, t.Version, err)
+		}
+	}
+
+	sum := sha256.Sum256(source)
+	return Prompt{
+		Template: t,
+		System:   strings.TrimSpace(system.String()),
+		User:     strings.TrimSpace(user.String()),
+		SHA256:   hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// Record stores which template produced content in meta.prompts[name].
+func (p Prompt) Record(meta map[string]any) {
+	recorded, _ := meta["prompts"].(map[string]any)
+	if recorded == nil {
+		recorded = map[string]any{}
+	}
+	recorded[p.Name] = map[string]any{
+		"name":    p.Name,
+		"version": p.Version,
+		"sha256":  p.SHA256,
+	}
+	meta["prompts"] = recorded
+}
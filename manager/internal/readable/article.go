@@ -0,0 +1,351 @@
+package readable
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Article is the readable part of a web page.
+type Article struct {
+	Title     string
+	Byline    string
+	Published *time.Time
+	// Text is the main content, one paragraph per line.
+	Text string
+}
+
+// minArticleLength is the shortest main text Extract trusts; below it the whole page text is used.
+const minArticleLength = 250
+
+var (
+	positiveHint = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text|blog`)
+	negativeHint = regexp.MustCompile(`(?i)comment|meta|footer|footnote|sidebar|sponsor|advert|\bads?\b|share|social|related|promo|nav|menu|subscribe|newsletter|cookie|banner|popup|modal|breadcrumb|widget|caption`)
+)
+
+// Extract finds the main text of an HTML page the way readability tools do: paragraphs score
+// their ancestors by length and commas, class/id names nudge the score, link-heavy blocks are
+// penalized, and the best-scoring container wins. Title, byline and publish date come from
+// JSON-LD, meta tags and common markup.
+func Extract(source string) Article {
+	doc, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return Article{}
+	}
+	article := metadata(doc)
+
+	if best := bestCandidate(doc); best != nil {
+		article.Text = nodeText(best, true)
+	}
+	if utf8.RuneCountInString(article.Text) < minArticleLength {
+		article.Text = nodeText(doc, false)
+	}
+	if article.Title == "" {
+		article.Title = documentTitle(doc)
+	}
+	return article
+}
+
+// bestCandidate returns the element with the highest content score, or nil.
+func bestCandidate(doc *html.Node) *html.Node {
+	scores := map[*html.Node]float64{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if skipped[n.DataAtom] || unlikely(n) {
+				return
+			}
+			if n.DataAtom == atom.P || n.DataAtom == atom.Pre || n.DataAtom == atom.Td || n.DataAtom == atom.Blockquote {
+				text := strings.Join(strings.Fields(innerText(n)), " ")
+				if length := utf8.RuneCountInString(text); length >= 25 {
+					score := 1 + float64(strings.Count(text, ",")) + min(float64(length)/100, 3)
+					if parent := n.Parent; parent != nil {
+						scores[parent] += score
+						if grandparent := parent.Parent; grandparent != nil {
+							scores[grandparent] += score / 2
+						}
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	var best *html.Node
+	bestScore := 0.0
+	for n, score := range scores {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		if positiveHint.MatchString(classAndID(n)) {
+			score += 25
+		}
+		if n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+			score += 10
+		}
+		score *= 1 - linkDensity(n)
+		if score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	return best
+}
+
+// unlikely reports page chrome: a negative class/id hint without a positive one. Structural
+// containers are never pruned, whatever their class says.
+func unlikely(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Html, atom.Body, atom.Article, atom.Main:
+		return false
+	}
+	hints := classAndID(n)
+	return negativeHint.MatchString(hints) && !positiveHint.MatchString(hints)
+}
+
+func classAndID(n *html.Node) string {
+	return attr(n, "class") + " " + attr(n, "id")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func innerText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			return
+		}
+		if n.Type == html.ElementNode && skipped[n.DataAtom] {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// linkDensity is the share of a node's text that sits inside links.
+func linkDensity(n *html.Node) float64 {
+	total := utf8.RuneCountInString(strings.Join(strings.Fields(innerText(n)), " "))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			linked += utf8.RuneCountInString(strings.Join(strings.Fields(innerText(n)), " "))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return min(float64(linked)/float64(total), 1)
+}
+
+// nodeText renders the text under n one block per line. pruneHints also drops descendants whose
+// class/id look like page chrome (share bars, related links) inside the article container.
+func nodeText(n *html.Node, pruneHints bool) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if skipped[n.DataAtom] {
+				return
+			}
+			if pruneHints && unlikely(n) {
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && blocks[n.DataAtom] {
+			b.WriteString("\n")
+		}
+	}
+	walk(n)
+	return collapse(b.String())
+}
+
+// metadata reads title, byline and publish date from JSON-LD first, then meta tags and markup.
+func metadata(doc *html.Node) Article {
+	var article Article
+	var metaTitle, metaAuthor, metaDate, markupAuthor, markupDate, h1 string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Script:
+				if strings.Contains(attr(n, "type"), "ld+json") && n.FirstChild != nil {
+					fromJSONLD(n.FirstChild.Data, &article)
+				}
+				return
+			case atom.Meta:
+				key := strings.ToLower(attr(n, "property") + attr(n, "name") + attr(n, "itemprop"))
+				content := strings.TrimSpace(attr(n, "content"))
+				switch key {
+				case "og:title", "twitter:title":
+					metaTitle = firstNonEmpty(metaTitle, content)
+				case "author", "article:author", "byl", "dc.creator", "parsely-author", "sailthru.author":
+					metaAuthor = firstNonEmpty(metaAuthor, content)
+				case "article:published_time", "datepublished", "date", "pubdate", "publishdate", "dc.date.issued", "parsely-pub-date", "sailthru.date":
+					metaDate = firstNonEmpty(metaDate, content)
+				}
+			case atom.Time:
+				markupDate = firstNonEmpty(markupDate, attr(n, "datetime"))
+			case atom.H1:
+				h1 = firstNonEmpty(h1, strings.Join(strings.Fields(innerText(n)), " "))
+			}
+			if attr(n, "rel") == "author" || attr(n, "itemprop") == "author" || strings.Contains(strings.ToLower(attr(n, "class")), "byline") {
+				markupAuthor = firstNonEmpty(markupAuthor, strings.Join(strings.Fields(innerText(n)), " "))
+			}
+			if attr(n, "itemprop") == "datePublished" {
+				markupDate = firstNonEmpty(markupDate, attr(n, "content"), attr(n, "datetime"))
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	article.Title = firstNonEmpty(article.Title, metaTitle, h1)
+	article.Byline = cleanByline(firstNonEmpty(article.Byline, metaAuthor, markupAuthor))
+	if article.Published == nil {
+		article.Published = parseDate(firstNonEmpty(metaDate, markupDate))
+	}
+	return article
+}
+
+// fromJSONLD fills empty fields from a schema.org Article/NewsArticle object (or @graph of them).
+func fromJSONLD(data string, article *Article) {
+	var raw any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &raw); err != nil {
+		return
+	}
+	var visit func(v any)
+	visit = func(v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				visit(item)
+			}
+		case map[string]any:
+			if graph, ok := v["@graph"]; ok {
+				visit(graph)
+			}
+			if !strings.Contains(strings.ToLower(jsonString(v["@type"])), "article") {
+				return
+			}
+			article.Title = firstNonEmpty(article.Title, jsonString(v["headline"]))
+			article.Byline = firstNonEmpty(article.Byline, jsonAuthor(v["author"]))
+			if article.Published == nil {
+				article.Published = parseDate(jsonString(v["datePublished"]))
+			}
+		}
+	}
+	visit(raw)
+}
+
+// jsonString returns a string value, or the first string of a list ("@type": ["NewsArticle"]).
+func jsonString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		var parts []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+	return ""
+}
+
+func jsonAuthor(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		return jsonString(v["name"])
+	case []any:
+		var names []string
+		for _, item := range v {
+			if name := jsonAuthor(item); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func cleanByline(byline string) string {
+	byline = strings.Join(strings.Fields(byline), " ")
+	if len(byline) > 3 && strings.EqualFold(byline[:3], "by ") {
+		byline = byline[3:]
+	}
+	if utf8.RuneCountInString(byline) > 100 {
+		return ""
+	}
+	return byline
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
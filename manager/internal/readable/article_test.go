@@ -0,0 +1,118 @@
+package readable
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		file      string
+		title     string
+		byline    string
+		published string
+		// text lists the lines the main text starts with; missing must not appear anywhere.
+		text    []string
+		missing []string
+	}{
+		{
+			file:      "news_jsonld.html",
+			title:     "Octopuses dream in colour, study finds",
+			byline:    "Ana Ruiz, Li Wei",
+			published: "2026-10-12T06:30:00Z",
+			text: []string{
+				"Octopuses dream in colour, study finds",
+				"Researchers filming sleeping octopuses saw their skin flash through patterns, colours and textures, the same displays the animals use while awake to hunt, hide and court.",
+				"The team, working in a lab tank in Okinawa, recorded dozens of these active sleep bouts, each lasting about a minute, and compared them with the patterns of waking animals.",
+			},
+			missing: []string{"Share on social media", "Most read", "First comment", "Copyright", "Science"},
+		},
+		{
+			file:      "blog_meta.html",
+			title:     "The tiny lighthouse keeper",
+			byline:    "Jane Doe",
+			published: "2026-09-30T18:00:00Z",
+			text: []string{
+				"On a rock two miles off the coast stands a lighthouse so small that its keeper, a retired sailor, has to climb out of the lantern room to clean the outside of the glass.",
+				"He has kept the light for twenty years, through storms, power cuts and one memorable winter when the supply boat could not land for six weeks, and he still writes the log by hand.",
+			},
+			missing: []string{"Related:", "Archive", "A list of links"},
+		},
+		{
+			file:      "markup.html",
+			title:     "Village bakery turns 100",
+			byline:    "Sam Lee",
+			published: "2026-10-01T00:00:00Z",
+			text: []string{
+				"The bakery on the corner of Mill Street opened in 1926, when bread was still delivered by bicycle, and the same family has run it for four generations.",
+			},
+		},
+		{
+			// Too little text for a main container: the whole visible page is used.
+			file:    "short.html",
+			title:   "Page not found",
+			text:    []string{"Sorry", "The page you are looking for has moved."},
+			missing: []string{"Home", "track("},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			article := Extract(string(data))
+			if article.Title != tt.title {
+				t.Errorf("title = %q, want %q", article.Title, tt.title)
+			}
+			if article.Byline != tt.byline {
+				t.Errorf("byline = %q, want %q", article.Byline, tt.byline)
+			}
+			published := ""
+			if article.Published != nil {
+				published = article.Published.UTC().Format(time.RFC3339)
+			}
+			if published != tt.published {
+				t.Errorf("published = %q, want %q", published, tt.published)
+			}
+			if want := strings.Join(tt.text, "\n"); !strings.HasPrefix(article.Text, want) {
+				t.Errorf("text =\n%s\nwant it to start with\n%s", article.Text, want)
+			}
+			for _, s := range tt.missing {
+				if strings.Contains(article.Text, s) {
+					t.Errorf("text contains %q:\n%s", s, article.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanByline(t *testing.T) {
+	tests := map[string]string{
+		"By  Jane\n Doe":         "Jane Doe",
+		"by Sam":                 "Sam",
+		"Byron Smith":            "Byron Smith",
+		strings.Repeat("x", 101): "",
+	}
+	for in, want := range tests {
+		if got := cleanByline(in); got != want {
+			t.Errorf("cleanByline(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	text := strings.Repeat("a", 95) + "\n" + strings.Repeat("b", 20)
+	if got := Truncate(text, 100); got != strings.Repeat("a", 95) {
+		t.Errorf("Truncate at a late line break = %q", got)
+	}
+	if got := Truncate("short", 100); got != "short" {
+		t.Errorf("Truncate short text = %q", got)
+	}
+	if got := Truncate("héllo world", 5); got != "héllo" {
+		t.Errorf("Truncate counts runes: %q", got)
+	}
+}
@@ -0,0 +1,76 @@
+package readable
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skipped elements never contain article text.
+var skipped = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Iframe:   true,
+	atom.Form:     true,
+	atom.Button:   true,
+	atom.Select:   true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Head:     true,
+}
+
+// blocks end a line of text.
+var blocks = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Article: true, atom.Section: true, atom.Main: true, atom.Blockquote: true,
+	atom.Pre: true, atom.Figcaption: true, atom.Dd: true, atom.Dt: true, atom.Table: true,
+	atom.Ul: true, atom.Ol: true, atom.Hr: true,
+}
+
+// documentTitle returns the <title> text.
+func documentTitle(doc *html.Node) string {
+	var find func(n *html.Node) string
+	find = func(n *html.Node) string {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Title && n.FirstChild != nil {
+			return strings.Join(strings.Fields(n.FirstChild.Data), " ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if title := find(c); title != "" {
+				return title
+			}
+		}
+		return ""
+	}
+	return find(doc)
+}
+
+// Truncate cuts text to at most max runes, at a line break when there is one in the last tenth.
+func Truncate(text string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	cut := string([]rune(text)[:max])
+	if i := strings.LastIndex(cut, "\n"); i > len(cut)*9/10 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut)
+}
+
+// collapse squeezes runs of spaces inside lines and drops empty lines.
+func collapse(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,300 @@
+package subtitles
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Alignment scoring for Needleman-Wunsch over normalized tokens.
+const (
+	alignMatch    = 2
+	alignMismatch = -1
+	alignGap      = -1
+)
+
+// AlignResult summarizes how well the original text matched the transcript.
+type AlignResult struct {
+	// Score is matched tokens divided by the longer token sequence (0..1).
+	Score            float64
+	Matched          int
+	OriginalTokens   int
+	TranscriptTokens int
+}
+
+// AlignCaptions replaces the transcript words in captions with the original words, keeping the
+// transcript timings. Words are aligned with Needleman-Wunsch over normalized tokens (lowercase,
+// punctuation stripped, numbers spelled out) so dropped, inserted or merged words only affect
+// their neighbourhood instead of shifting every following caption.
+// Captions that end up with no words are dropped.
+func AlignCaptions(captions []Caption, originalText string) ([]Caption, AlignResult) {
+	origWords := strings.Fields(originalText)
+
+	// Flatten original words into tokens, remembering which word each token came from.
+	var origTokens []string
+	var origTokenWord []int
+	for i, word := range origWords {
+		for _, tok := range normalizeWord(word) {
+			origTokens = append(origTokens, tok)
+			origTokenWord = append(origTokenWord, i)
+		}
+	}
+
+	// Flatten transcript words into tokens, remembering which caption each token came from.
+	var transTokens []string
+	var transTokenCaption []int
+	for i, caption := range captions {
+		for _, word := range strings.Fields(caption.Text) {
+			for _, tok := range normalizeWord(word) {
+				transTokens = append(transTokens, tok)
+				transTokenCaption = append(transTokenCaption, i)
+			}
+		}
+	}
+
+	result := AlignResult{OriginalTokens: len(origTokens), TranscriptTokens: len(transTokens)}
+	if len(captions) == 0 {
+		return nil, result
+	}
+
+	// wordCaption[i] is the caption original word i is placed in (-1 until known).
+	wordCaption := make([]int, len(origWords))
+	for i := range wordCaption {
+		wordCaption[i] = -1
+	}
+	pairs := alignTokens(origTokens, transTokens)
+	for _, p := range pairs {
+		if p.a < 0 || p.b < 0 {
+			continue
+		}
+		if origTokens[p.a] == transTokens[p.b] {
+			result.Matched++
+		}
+		word := origTokenWord[p.a]
+		if wordCaption[word] < 0 {
+			wordCaption[word] = transTokenCaption[p.b]
+		}
+	}
+	if longest := max(len(origTokens), len(transTokens)); longest > 0 {
+		result.Score = float64(result.Matched) / float64(longest)
+	}
+
+	// Words aligned only to gaps (insertions in the original) follow the previous word;
+	// leading ones go with the first placed word.
+	prev := -1
+	for i := range wordCaption {
+		if wordCaption[i] >= 0 {
+			prev = wordCaption[i]
+			continue
+		}
+		wordCaption[i] = prev
+	}
+	next := 0
+	for i := len(wordCaption) - 1; i >= 0; i-- {
+		if wordCaption[i] >= 0 {
+			next = wordCaption[i]
+			continue
+		}
+		wordCaption[i] = next
+	}
+
+	texts := make([][]string, len(captions))
+	for i, word := range origWords {
+		texts[wordCaption[i]] = append(texts[wordCaption[i]], word)
+	}
+	out := make([]Caption, 0, len(captions))
+	for i, caption := range captions {
+		if len(texts[i]) == 0 {
+			continue
+		}
+		caption.Text = strings.Join(texts[i], " ")
+		out = append(out, caption)
+	}
+	return out, result
+}
+
+type alignPair struct {
+	a int
+	b int
+}
+
+// alignTokens returns the global alignment of a and b as index pairs; -1 marks a gap.
+func alignTokens(a, b []string) []alignPair {
+	n, m := len(a), len(b)
+	score := make([][]int, n+1)
+	for i := range score {
+		score[i] = make([]int, m+1)
+		score[i][0] = i * alignGap
+	}
+	for j := 0; j <= m; j++ {
+		score[0][j] = j * alignGap
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			diag := score[i-1][j-1] + alignMismatch
+			if a[i-1] == b[j-1] {
+				diag = score[i-1][j-1] + alignMatch
+			}
+			score[i][j] = max(diag, score[i-1][j]+alignGap, score[i][j-1]+alignGap)
+		}
+	}
+
+	pairs := make([]alignPair, 0, max(n, m))
+	i, j := n, m
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && score[i][j] == score[i-1][j-1]+substitutionScore(a[i-1], b[j-1]):
+			pairs = append(pairs, alignPair{a: i - 1, b: j - 1})
+			i--
+			j--
+		case i > 0 && score[i][j] == score[i-1][j]+alignGap:
+			pairs = append(pairs, alignPair{a: i - 1, b: -1})
+			i--
+		default:
+			pairs = append(pairs, alignPair{a: -1, b: j - 1})
+			j--
+		}
+	}
+	for l, r := 0, len(pairs)-1; l < r; l, r = l+1, r-1 {
+		pairs[l], pairs[r] = pairs[r], pairs[l]
+	}
+	return pairs
+}
+
+func substitutionScore(a, b string) int {
+	if a == b {
+		return alignMatch
+	}
+	return alignMismatch
+}
+
+// normalizeWord lowercases a word, strips punctuation and spells out numbers so "1,000",
+// "one thousand" and "1000" all compare equal. A word may expand to several tokens.
+func normalizeWord(word string) []string {
+	word = strings.ToLower(word)
+	word = strings.NewReplacer("%", " percent ", "&", " and ", "-", " ", "–", " ", "—", " ", "/", " ").Replace(word)
+
+	var tokens []string
+	for _, part := range strings.Fields(word) {
+		part = strings.TrimFunc(part, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if part == "" {
+			continue
+		}
+		if words, ok := spellNumber(part); ok {
+			tokens = append(tokens, words...)
+			continue
+		}
+		var b strings.Builder
+		for _, r := range part {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
+		}
+		if b.Len() > 0 {
+			tokens = append(tokens, b.String())
+		}
+	}
+	return tokens
+}
+
+var (
+	smallNumbers = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tensNumbers = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scaleWords  = []struct {
+		value int64
+		word  string
+	}{
+		{1_000_000_000_000, "trillion"},
+		{1_000_000_000, "billion"},
+		{1_000_000, "million"},
+		{1_000, "thousand"},
+	}
+	ordinalWords = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+)
+
+// spellNumber turns "1,234", "3.5" or "21st" into words. It reports false for anything that is
+// not a plain number.
+func spellNumber(s string) ([]string, bool) {
+	ordinal := false
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if len(s) > len(suffix) && strings.HasSuffix(s, suffix) && isDigits(strings.TrimSuffix(s, suffix)) {
+			s = strings.TrimSuffix(s, suffix)
+			ordinal = true
+			break
+		}
+	}
+	s = strings.ReplaceAll(s, ",", "")
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	if !isDigits(intPart) || (hasFrac && !isDigits(fracPart)) {
+		return nil, false
+	}
+	n, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || n >= 1_000_000_000_000_000 {
+		return nil, false
+	}
+
+	words := cardinalWords(n)
+	if ordinal && len(words) > 0 {
+		last := words[len(words)-1]
+		switch {
+		case ordinalWords[last] != "":
+			last = ordinalWords[last]
+		case strings.HasSuffix(last, "y"):
+			last = strings.TrimSuffix(last, "y") + "ieth"
+		default:
+			last += "th"
+		}
+		words[len(words)-1] = last
+	}
+	if hasFrac {
+		words = append(words, "point")
+		for _, r := range fracPart {
+			words = append(words, smallNumbers[r-'0'])
+		}
+	}
+	return words, true
+}
+
+func cardinalWords(n int64) []string {
+	if n < 20 {
+		return []string{smallNumbers[n]}
+	}
+	var words []string
+	for _, scale := range scaleWords {
+		if n >= scale.value {
+			words = append(words, cardinalWords(n/scale.value)...)
+			words = append(words, scale.word)
+			n %= scale.value
+		}
+	}
+	if n >= 100 {
+		words = append(words, smallNumbers[n/100], "hundred")
+		n %= 100
+	}
+	if n >= 20 {
+		words = append(words, tensNumbers[n/10])
+		n %= 10
+		if n > 0 {
+			words = append(words, smallNumbers[n])
+		}
+	} else if n > 0 {
+		words = append(words, smallNumbers[n])
+	}
+	return words
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package subtitles
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var assOverrideRegex = regexp.MustCompile(`\{[^}]*\}`)
+
+// defaultASSFormat is the V4+ event format assumed when the [Events] section has no Format line.
+var defaultASSFormat = []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+
+// ParseASS parses the Dialogue events of an Advanced SubStation Alpha script. Override tags
+// ({\b1}, {\pos(..)}) are stripped and \N / \n become newlines, \h a space.
+// Block numbers in errors count Dialogue events.
+func ParseASS(input string) ([]Caption, error) {
+	var captions []Caption
+	var errs []error
+	inEvents := false
+	format := defaultASSFormat
+	event := 0
+	for i, line := range strings.Split(NormalizeText(input), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !inEvents {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Format":
+			fields := strings.Split(value, ",")
+			format = make([]string, len(fields))
+			for j, f := range fields {
+				format[j] = strings.ToLower(strings.TrimSpace(f))
+			}
+		case "Dialogue":
+			event++
+			caption, err := parseASSDialogue(format, value)
+			if err != nil {
+				errs = append(errs, &ParseError{Format: "ass", Block: event, Line: i + 1, Msg: err.Error()})
+				continue
+			}
+			captions = append(captions, caption)
+		}
+	}
+	return captions, errors.Join(errs...)
+}
+
+func parseASSDialogue(format []string, value string) (Caption, error) {
+	// Text is always the last field and may itself contain commas.
+	fields := strings.SplitN(value, ",", len(format))
+	if len(fields) != len(format) {
+		return Caption{}, fmt.Errorf("expected %d fields, got %d", len(format), len(fields))
+	}
+	var caption Caption
+	var haveStart, haveEnd, haveText bool
+	for i, name := range format {
+		field := strings.TrimSpace(fields[i])
+		var err error
+		switch name {
+		case "start":
+			caption.Start, err = parseClock(field)
+			haveStart = true
+		case "end":
+			caption.End, err = parseClock(field)
+			haveEnd = true
+		case "text":
+			caption.Text = assToPlain(fields[i])
+			haveText = true
+		}
+		if err != nil {
+			return Caption{}, err
+		}
+	}
+	if !haveStart || !haveEnd || !haveText {
+		return Caption{}, errors.New("format is missing Start, End or Text")
+	}
+	if caption.End < caption.Start {
+		return Caption{}, fmt.Errorf("end %s before start %s", FormatASSTime(caption.End), FormatASSTime(caption.Start))
+	}
+	return caption, nil
+}
+
+func assToPlain(text string) string {
+	text = assOverrideRegex.ReplaceAllString(text, "")
+	text = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+// SerializeASS writes a minimal script (one Default style) with one Dialogue event per caption.
+func SerializeASS(captions []Caption) string {
+	var builder strings.Builder
+	builder.WriteString("[Script Info]\n")
+	builder.WriteString("ScriptType: v4.00+\n")
+	builder.WriteString("PlayResX: 1920\n")
+	builder.WriteString("PlayResY: 1080\n")
+	builder.WriteString("\n[V4+ Styles]\n")
+	builder.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	builder.WriteString("Style: Default,Arial,64,&H00FFFFFF,&H000000FF,&H00000000,&H64000000,0,0,0,0,100,100,0,0,1,3,0,2,40,40,60,1\n")
+	builder.WriteString("\n[Events]\n")
+	builder.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, caption := range captions {
+		text := strings.ReplaceAll(caption.Text, "\n", `\N`)
+		builder.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", FormatASSTime(caption.Start), FormatASSTime(caption.End), text))
+	}
+	return builder.String()
+}
@@ -0,0 +1,116 @@
+package subtitles
+
+import (
+	"strings"
+	"time"
+)
+
+// The operations below return new slices and never modify their input.
+
+// Shift moves every caption by offset (negative moves earlier). Times are clamped at zero and
+// captions that end up entirely before zero are dropped.
+func Shift(captions []Caption, offset time.Duration) []Caption {
+	out := make([]Caption, 0, len(captions))
+	for _, c := range captions {
+		c.Start += offset
+		c.End += offset
+		if c.End <= 0 {
+			continue
+		}
+		if c.Start < 0 {
+			c.Start = 0
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// Scale multiplies every timestamp by factor, e.g. to follow audio that was sped up or slowed down
+// (new duration / old duration).
+func Scale(captions []Caption, factor float64) []Caption {
+	out := make([]Caption, len(captions))
+	for i, c := range captions {
+		c.Start = time.Duration(float64(c.Start) * factor)
+		c.End = time.Duration(float64(c.End) * factor)
+		out[i] = c
+	}
+	return out
+}
+
+// MergeAdjacent joins consecutive captions when the gap between them is at most maxGap and the
+// merged text (joined with a space) stays within maxChars. maxChars <= 0 means no length limit.
+func MergeAdjacent(captions []Caption, maxGap time.Duration, maxChars int) []Caption {
+	out := make([]Caption, 0, len(captions))
+	for _, c := range captions {
+		if len(out) > 0 {
+			last := &out[len(out)-1]
+			merged := strings.TrimSpace(last.Text + " " + c.Text)
+			if c.Start-last.End <= maxGap && (maxChars <= 0 || len([]rune(merged)) <= maxChars) {
+				last.Text = merged
+				if c.End > last.End {
+					last.End = c.End
+				}
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// SplitLong breaks captions longer than maxChars at word boundaries. The original time span is
+// divided between the pieces in proportion to their length.
+func SplitLong(captions []Caption, maxChars int) []Caption {
+	if maxChars <= 0 {
+		return append([]Caption(nil), captions...)
+	}
+	out := make([]Caption, 0, len(captions))
+	for _, c := range captions {
+		text := strings.Join(strings.Fields(c.Text), " ")
+		if len([]rune(text)) <= maxChars {
+			out = append(out, c)
+			continue
+		}
+		pieces := wrapWords(strings.Fields(text), maxChars)
+		total := 0
+		for _, p := range pieces {
+			total += len([]rune(p))
+		}
+		span := c.Duration()
+		start := c.Start
+		done := 0
+		for i, p := range pieces {
+			done += len([]rune(p))
+			end := c.Start + time.Duration(float64(span)*float64(done)/float64(total))
+			if i == len(pieces)-1 {
+				end = c.End
+			}
+			out = append(out, Caption{Start: start, End: end, Text: p})
+			start = end
+		}
+	}
+	return out
+}
+
+// wrapWords greedily packs words into lines of at most maxChars runes. A single word longer than
+// maxChars gets a line of its own.
+func wrapWords(words []string, maxChars int) []string {
+	var lines []string
+	current := ""
+	for _, w := range words {
+		if current == "" {
+			current = w
+			continue
+		}
+		if len([]rune(current))+1+len([]rune(w)) > maxChars {
+			lines = append(lines, current)
+			current = w
+			continue
+		}
+		current += " " + w
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
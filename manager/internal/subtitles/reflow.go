@@ -0,0 +1,204 @@
+package subtitles
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Style is a subtitle layout profile used by Reflow.
+type Style struct {
+	Name            string
+	MaxCharsPerLine int
+	MaxLines        int
+	MinDuration     time.Duration
+	MaxDuration     time.Duration
+	// MaxCPS is the reading speed limit in characters per second (0 disables it).
+	MaxCPS float64
+	// PauseBreak starts a new caption when the speaker pauses at least this long between words.
+	PauseBreak time.Duration
+}
+
+var styleProfiles = map[string]Style{
+	// Vertical video (shorts/reels): narrow frame, short lines.
+	"vertical": {Name: "vertical", MaxCharsPerLine: 28, MaxLines: 2, MinDuration: time.Second, MaxDuration: 5 * time.Second, MaxCPS: 17, PauseBreak: 600 * time.Millisecond},
+	// Landscape video, close to common broadcast guidelines.
+	"landscape": {Name: "landscape", MaxCharsPerLine: 42, MaxLines: 2, MinDuration: time.Second, MaxDuration: 7 * time.Second, MaxCPS: 20, PauseBreak: 800 * time.Millisecond},
+}
+
+// StyleProfile returns a built-in profile ("vertical" or "landscape").
+func StyleProfile(name string) (Style, bool) {
+	style, ok := styleProfiles[strings.ToLower(strings.TrimSpace(name))]
+	return style, ok
+}
+
+func (s Style) withDefaults() Style {
+	if s.MaxCharsPerLine <= 0 {
+		s.MaxCharsPerLine = 42
+	}
+	if s.MaxLines <= 0 {
+		s.MaxLines = 2
+	}
+	if s.MaxDuration <= 0 {
+		s.MaxDuration = 7 * time.Second
+	}
+	if s.MinDuration > s.MaxDuration {
+		s.MinDuration = s.MaxDuration
+	}
+	return s
+}
+
+// Word is a single word with its (possibly interpolated) timing.
+type Word struct {
+	Text  string
+	Start time.Duration
+	End   time.Duration
+}
+
+// WordTimings splits captions into words. Transcripts only carry caption-level timings, so each
+// caption's span is divided between its words in proportion to their length.
+func WordTimings(captions []Caption) []Word {
+	var words []Word
+	for _, c := range captions {
+		fields := strings.Fields(c.Text)
+		if len(fields) == 0 {
+			continue
+		}
+		total := 0
+		for _, f := range fields {
+			total += utf8.RuneCountInString(f) + 1
+		}
+		span := c.Duration()
+		done := 0
+		start := c.Start
+		for i, f := range fields {
+			done += utf8.RuneCountInString(f) + 1
+			end := c.Start + time.Duration(float64(span)*float64(done)/float64(total))
+			if i == len(fields)-1 {
+				end = c.End
+			}
+			words = append(words, Word{Text: f, Start: start, End: end})
+			start = end
+		}
+	}
+	return words
+}
+
+// Reflow re-segments captions for the given style: captions hold at most MaxLines lines of
+// MaxCharsPerLine characters, break after sentence punctuation and on pauses, prefer to split
+// at commas when they run out of room, and are stretched (into the following gap) to satisfy
+// the minimum duration and reading speed without exceeding MaxDuration.
+func Reflow(captions []Caption, style Style) []Caption {
+	style = style.withDefaults()
+	words := WordTimings(captions)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var groups [][]Word
+	var current []Word
+	flush := func() {
+		if len(current) > 0 {
+			groups = append(groups, current)
+			current = nil
+		}
+	}
+	for i, w := range words {
+		if len(current) > 0 {
+			last := current[len(current)-1]
+			candidate := append(append([]Word(nil), current...), w)
+			switch {
+			case style.PauseBreak > 0 && w.Start-last.End >= style.PauseBreak:
+				flush()
+			case w.End-current[0].Start > style.MaxDuration, !fitsLayout(candidate, style):
+				// Out of room: split at the last clause boundary in the second half of the
+				// caption when there is one, carrying the tail over to the next caption.
+				cut := len(current)
+				for k := len(current) - 1; k >= len(current)/2 && k > 0; k-- {
+					if endsClause(current[k-1].Text) {
+						cut = k
+						break
+					}
+				}
+				tail := append([]Word(nil), current[cut:]...)
+				current = current[:cut]
+				flush()
+				current = tail
+			}
+		}
+		current = append(current, w)
+		if endsSentence(w.Text) && i < len(words)-1 {
+			flush()
+		}
+	}
+	flush()
+
+	out := make([]Caption, 0, len(groups))
+	for _, g := range groups {
+		texts := make([]string, len(g))
+		for i, w := range g {
+			texts[i] = w.Text
+		}
+		out = append(out, Caption{
+			Start: g[0].Start,
+			End:   g[len(g)-1].End,
+			Text:  strings.Join(wrapWords(texts, style.MaxCharsPerLine), "\n"),
+		})
+	}
+	applyDurationLimits(out, style)
+	return out
+}
+
+func fitsLayout(words []Word, style Style) bool {
+	texts := make([]string, len(words))
+	for i, w := range words {
+		texts[i] = w.Text
+	}
+	lines := wrapWords(texts, style.MaxCharsPerLine)
+	if len(lines) > style.MaxLines {
+		return false
+	}
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > style.MaxCharsPerLine {
+			// A single over-long word is allowed only when it is alone.
+			return len(words) == 1
+		}
+	}
+	return true
+}
+
+func applyDurationLimits(captions []Caption, style Style) {
+	for i := range captions {
+		c := &captions[i]
+		limit := c.Start + style.MaxDuration
+		if i+1 < len(captions) && captions[i+1].Start < limit {
+			limit = captions[i+1].Start
+		}
+		want := c.Start + style.MinDuration
+		if style.MaxCPS > 0 {
+			chars := utf8.RuneCountInString(strings.ReplaceAll(c.Text, "\n", " "))
+			if need := c.Start + time.Duration(float64(chars)/style.MaxCPS*float64(time.Second)); need > want {
+				want = need
+			}
+		}
+		if c.End < want {
+			c.End = max(c.End, min(want, limit))
+		}
+		if c.End > c.Start+style.MaxDuration {
+			c.End = c.Start + style.MaxDuration
+		}
+	}
+}
+
+func endsSentence(word string) bool {
+	word = strings.TrimRight(word, `"')]”’`)
+	return strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "?")
+}
+
+func endsClause(word string) bool {
+	if endsSentence(word) {
+		return true
+	}
+	word = strings.TrimRight(word, `"')]”’`)
+	return strings.HasSuffix(word, ",") || strings.HasSuffix(word, ";") || strings.HasSuffix(word, ":") || strings.HasSuffix(word, "—")
+}
@@ -1,57 +1,98 @@
 package subtitles
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Caption struct {
-	StartTime string
-	EndTime   string
-	Text      string
+	Start time.Duration
+	End   time.Duration
+	Text  string
 }
 
-var timeRegex = regexp.MustCompile(`(\d\d:\d\d:\d\d,\d\d\d)\s-->\s(\d\d:\d\d:\d\d,\d\d\d)`)
-
-func ParseSRT(input string) []Caption {
-	trimmed := strings.TrimSpace(input)
-	if trimmed == "" {
-		return nil
+// Duration is End-Start (never negative).
+func (c Caption) Duration() time.Duration {
+	if c.End < c.Start {
+		return 0
 	}
-	blocks := splitBlocks(trimmed)
-	captions := make([]Caption, 0, len(blocks))
-	for _, block := range blocks {
-		lines := splitLines(block)
-		if len(lines) < 2 {
-			continue
+	return c.End - c.Start
+}
+
+// ParseError describes a block that could not be parsed. Block is 1-based in input order
+// (SRT/WebVTT blocks separated by blank lines, ASS Dialogue events); Line is the 1-based input line.
+type ParseError struct {
+	Format string
+	Block  int
+	Line   int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s block %d (line %d): %s", e.Format, e.Block, e.Line, e.Msg)
+}
+
+// Time ranges accept both "," and "." millisecond separators and optional trailing cue settings.
+var timeRangeRegex = regexp.MustCompile(`^\s*(\S+)\s+-->\s+(\S+)`)
+
+// ParseSRT parses SubRip captions. Blocks that fail to parse are skipped and reported in the
+// returned error (errors.Join of *ParseError), so callers can decide whether partial output is usable.
+func ParseSRT(input string) ([]Caption, error) {
+	captions, _, err := parseSRTBlocks(input)
+	return captions, err
+}
+
+// parseSRTBlocks is ParseSRT that also returns the 1-based block number each caption came from,
+// which differs from its position once a block has been skipped.
+func parseSRTBlocks(input string) ([]Caption, []int, error) {
+	var captions []Caption
+	var blocks []int
+	var errs []error
+	for n, block := range splitBlocks(NormalizeText(input)) {
+		fail := func(line int, format string, args ...any) {
+			errs = append(errs, &ParseError{Format: "srt", Block: n + 1, Line: block.line + line, Msg: fmt.Sprintf(format, args...)})
 		}
-		// First line is index; second line is time range.
-		matches := timeRegex.FindStringSubmatch(lines[1])
-		if len(matches) < 3 {
+		lines := block.lines
+		// The numeric index line is optional in the wild; accept blocks that start with the time range.
+		timeLine := 0
+		if !strings.Contains(lines[0], "-->") {
+			if _, err := strconv.Atoi(strings.TrimSpace(lines[0])); err != nil {
+				fail(0, "invalid index %q", lines[0])
+				continue
+			}
+			timeLine = 1
+		}
+		if timeLine >= len(lines) {
+			fail(0, "missing time range")
 			continue
 		}
-		text := ""
-		if len(lines) > 2 {
-			text = strings.Join(lines[2:], "\n")
+		start, end, err := parseTimeRange(lines[timeLine], parseClock)
+		if err != nil {
+			fail(timeLine, "%v", err)
+			continue
 		}
 		captions = append(captions, Caption{
-			StartTime: matches[1],
-			EndTime:   matches[2],
-			Text:      strings.TrimRight(text, "\n"),
+			Start: start,
+			End:   end,
+			Text:  strings.Join(lines[timeLine+1:], "\n"),
 		})
+		blocks = append(blocks, n+1)
 	}
-	return captions
+	return captions, blocks, errors.Join(errs...)
 }
 
 func SerializeSRT(captions []Caption) string {
 	var builder strings.Builder
 	for idx, caption := range captions {
-		builder.WriteString(intToString(idx + 1))
+		builder.WriteString(strconv.Itoa(idx + 1))
 		builder.WriteString("\n")
-		builder.WriteString(caption.StartTime)
+		builder.WriteString(FormatSRTTime(caption.Start))
 		builder.WriteString(" --> ")
-		builder.WriteString(caption.EndTime)
+		builder.WriteString(FormatSRTTime(caption.End))
 		builder.WriteString("\n")
 		builder.WriteString(caption.Text)
 		builder.WriteString("\n\n")
@@ -62,19 +103,48 @@ func SerializeSRT(captions []Caption) string {
 func NormalizeText(input string) string {
 	text := strings.ReplaceAll(input, "\r\n", "\n")
 	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
 	return strings.TrimRight(text, "\n")
 }
 
-func splitBlocks(input string) []string {
-	re := regexp.MustCompile(`\r?\n\r?\n+`)
-	return re.Split(input, -1)
+// textBlock is a run of non-blank lines plus the 1-based line number it starts at.
+type textBlock struct {
+	line  int
+	lines []string
 }
 
-func splitLines(input string) []string {
-	text := NormalizeText(input)
-	return strings.Split(text, "\n")
+func splitBlocks(input string) []textBlock {
+	var blocks []textBlock
+	var current *textBlock
+	for i, line := range strings.Split(input, "\n") {
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if current == nil {
+			blocks = append(blocks, textBlock{line: i + 1})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, strings.TrimRight(line, " \t"))
+	}
+	return blocks
 }
 
-func intToString(value int) string {
-	return strconv.Itoa(value)
+func parseTimeRange(line string, parse func(string) (time.Duration, error)) (time.Duration, time.Duration, error) {
+	matches := timeRangeRegex.FindStringSubmatch(line)
+	if len(matches) < 3 {
+		return 0, 0, fmt.Errorf("invalid time range %q", line)
+	}
+	start, err := parse(matches[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parse(matches[2])
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("end %s before start %s", matches[2], matches[1])
+	}
+	return start, end, nil
 }
@@ -0,0 +1,75 @@
+package subtitles
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseClock parses "[HH:]MM:SS(,|.)fff" as used by SRT and WebVTT. The fraction may have any
+// number of digits (ASS uses centiseconds).
+func parseClock(value string) (time.Duration, error) {
+	raw := value
+	value = strings.TrimSpace(value)
+	clock, frac, _ := strings.Cut(strings.Replace(value, ",", ".", 1), ".")
+	parts := strings.Split(clock, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", raw)
+	}
+	var total time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}[3-len(parts):]
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid timestamp %q", raw)
+		}
+		total += time.Duration(n) * units[i]
+	}
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		n, err := strconv.Atoi(frac)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", raw)
+		}
+		for i := len(frac); i < 9; i++ {
+			n *= 10
+		}
+		total += time.Duration(n)
+	}
+	return total, nil
+}
+
+// FormatSRTTime formats d as "HH:MM:SS,mmm".
+func FormatSRTTime(d time.Duration) string {
+	h, m, s, ms := splitClock(d, time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+}
+
+// FormatVTTTime formats d as "HH:MM:SS.mmm".
+func FormatVTTTime(d time.Duration) string {
+	h, m, s, ms := splitClock(d, time.Millisecond)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
+
+// FormatASSTime formats d as "H:MM:SS.cc".
+func FormatASSTime(d time.Duration) string {
+	h, m, s, cs := splitClock(d, 10*time.Millisecond)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, s, cs)
+}
+
+func splitClock(d time.Duration, unit time.Duration) (int64, int64, int64, int64) {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(unit)
+	h := int64(d / time.Hour)
+	d -= time.Duration(h) * time.Hour
+	m := int64(d / time.Minute)
+	d -= time.Duration(m) * time.Minute
+	s := int64(d / time.Second)
+	d -= time.Duration(s) * time.Second
+	return h, m, s, int64(d / unit)
+}
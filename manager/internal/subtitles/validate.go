@@ -0,0 +1,118 @@
+package subtitles
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Issue kinds reported by Validate.
+const (
+	IssueParse        = "parse_error"
+	IssueNoCaptions   = "no_captions"
+	IssueEmptyText    = "empty_text"
+	IssueZeroLength   = "zero_length"
+	IssueOutOfOrder   = "out_of_order"
+	IssueOverlap      = "overlap"
+	IssuePastAudioEnd = "past_audio_end"
+	IssueLowAlignment = "low_alignment"
+)
+
+// Issue is a single validation problem. Block is the 1-based SRT block number (0 when the issue
+// applies to the whole track); for captions not parsed from SRT it is the caption's position, the
+// number SerializeSRT gives it.
+type Issue struct {
+	Kind  string
+	Block int
+	Msg   string
+}
+
+func (i Issue) String() string {
+	if i.Block > 0 {
+		return fmt.Sprintf("%s (block %d): %s", i.Kind, i.Block, i.Msg)
+	}
+	return fmt.Sprintf("%s: %s", i.Kind, i.Msg)
+}
+
+// ValidateOptions enables the checks that need context beyond the captions themselves.
+type ValidateOptions struct {
+	// AudioDuration is the narration length; captions ending after it (plus Tolerance) are reported.
+	AudioDuration time.Duration
+	// Tolerance absorbs rounding between the transcript and the probed audio duration.
+	Tolerance time.Duration
+	// OriginalText and MinAlignmentScore report transcripts that barely match the script.
+	OriginalText      string
+	MinAlignmentScore float64
+}
+
+// ValidateSRT parses srt and validates the result; parse errors are reported as issues.
+func ValidateSRT(srt string, opts ValidateOptions) []Issue {
+	captions, blocks, err := parseSRTBlocks(srt)
+	var issues []Issue
+	if err != nil {
+		var perr *ParseError
+		for _, e := range unwrapJoined(err) {
+			if errors.As(e, &perr) {
+				issues = append(issues, Issue{Kind: IssueParse, Block: perr.Block, Msg: perr.Msg})
+			} else {
+				issues = append(issues, Issue{Kind: IssueParse, Msg: e.Error()})
+			}
+		}
+	}
+	return append(issues, validate(captions, blocks, opts)...)
+}
+
+// Validate checks caption timings and text.
+func Validate(captions []Caption, opts ValidateOptions) []Issue {
+	return validate(captions, nil, opts)
+}
+
+// validate reports issues against blocks[i], the source block of captions[i], or against the
+// caption position when blocks is nil.
+func validate(captions []Caption, blocks []int, opts ValidateOptions) []Issue {
+	block := func(i int) int {
+		if blocks != nil {
+			return blocks[i]
+		}
+		return i + 1
+	}
+	if len(captions) == 0 {
+		return []Issue{{Kind: IssueNoCaptions, Msg: "no captions"}}
+	}
+	var issues []Issue
+	for i, c := range captions {
+		n := block(i)
+		if strings.TrimSpace(c.Text) == "" {
+			issues = append(issues, Issue{Kind: IssueEmptyText, Block: n, Msg: "caption has no text"})
+		}
+		if c.End <= c.Start {
+			issues = append(issues, Issue{Kind: IssueZeroLength, Block: n, Msg: fmt.Sprintf("%s --> %s", FormatSRTTime(c.Start), FormatSRTTime(c.End))})
+		}
+		if i > 0 {
+			prev := captions[i-1]
+			switch {
+			case c.Start < prev.Start:
+				issues = append(issues, Issue{Kind: IssueOutOfOrder, Block: n, Msg: fmt.Sprintf("starts at %s before block %d (%s)", FormatSRTTime(c.Start), block(i-1), FormatSRTTime(prev.Start))})
+			case c.Start < prev.End:
+				issues = append(issues, Issue{Kind: IssueOverlap, Block: n, Msg: fmt.Sprintf("starts at %s before block %d ends (%s)", FormatSRTTime(c.Start), block(i-1), FormatSRTTime(prev.End))})
+			}
+		}
+		if opts.AudioDuration > 0 && c.End > opts.AudioDuration+opts.Tolerance {
+			issues = append(issues, Issue{Kind: IssuePastAudioEnd, Block: n, Msg: fmt.Sprintf("ends at %s, audio is %s", FormatSRTTime(c.End), FormatSRTTime(opts.AudioDuration))})
+		}
+	}
+	if strings.TrimSpace(opts.OriginalText) != "" && opts.MinAlignmentScore > 0 {
+		if _, result := AlignCaptions(captions, opts.OriginalText); result.Score < opts.MinAlignmentScore {
+			issues = append(issues, Issue{Kind: IssueLowAlignment, Msg: fmt.Sprintf("alignment score %.2f below %.2f", result.Score, opts.MinAlignmentScore)})
+		}
+	}
+	return issues
+}
+
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,40 @@
+package subtitles
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateSRTReportsSourceBlocks(t *testing.T) {
+	srt := strings.Join([]string{
+		"1\n00:00:01,000 --> 00:00:02,000\nFirst",
+		"2\nnot a time range\nBroken",
+		"3\n00:00:03,000 --> 00:00:05,000\nThird",
+		"4\n00:00:04,000 --> 00:00:06,000\nFourth",
+	}, "\n\n")
+
+	issues := ValidateSRT(srt, ValidateOptions{})
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`parse_error (block 2): invalid time range "not a time range"`,
+		"overlap (block 4): starts at 00:00:04,000 before block 3 ends (00:00:05,000)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateNumbersCaptionsByPosition(t *testing.T) {
+	captions := []Caption{
+		{Start: 0, End: 2 * time.Second, Text: "one"},
+		{Start: time.Second, End: 3 * time.Second, Text: "two"},
+	}
+	issues := Validate(captions, ValidateOptions{})
+	if len(issues) != 1 || issues[0].Kind != IssueOverlap || issues[0].Block != 2 || !strings.Contains(issues[0].Msg, "before block 1 ends") {
+		t.Errorf("issues = %v", issues)
+	}
+}
@@ -0,0 +1,64 @@
+package subtitles
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseVTT parses WebVTT cues. NOTE, STYLE and REGION blocks are skipped; cue identifiers and cue
+// settings are accepted and dropped. Like ParseSRT, bad blocks are skipped and reported.
+func ParseVTT(input string) ([]Caption, error) {
+	blocks := splitBlocks(NormalizeText(input))
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	header := blocks[0]
+	if !strings.HasPrefix(header.lines[0], "WEBVTT") {
+		return nil, &ParseError{Format: "vtt", Block: 1, Line: header.line, Msg: "missing WEBVTT header"}
+	}
+
+	var captions []Caption
+	var errs []error
+	for n, block := range blocks[1:] {
+		first := block.lines[0]
+		if strings.HasPrefix(first, "NOTE") || first == "STYLE" || first == "REGION" {
+			continue
+		}
+		timeLine := 0
+		if !strings.Contains(first, "-->") {
+			timeLine = 1
+		}
+		if timeLine >= len(block.lines) {
+			errs = append(errs, &ParseError{Format: "vtt", Block: n + 2, Line: block.line, Msg: "missing time range"})
+			continue
+		}
+		start, end, err := parseTimeRange(block.lines[timeLine], parseClock)
+		if err != nil {
+			errs = append(errs, &ParseError{Format: "vtt", Block: n + 2, Line: block.line + timeLine, Msg: err.Error()})
+			continue
+		}
+		captions = append(captions, Caption{
+			Start: start,
+			End:   end,
+			Text:  strings.Join(block.lines[timeLine+1:], "\n"),
+		})
+	}
+	return captions, errors.Join(errs...)
+}
+
+func SerializeVTT(captions []Caption) string {
+	var builder strings.Builder
+	builder.WriteString("WEBVTT\n\n")
+	for idx, caption := range captions {
+		builder.WriteString(fmt.Sprintf("%d\n", idx+1))
+		builder.WriteString(FormatVTTTime(caption.Start))
+		builder.WriteString(" --> ")
+		builder.WriteString(FormatVTTTime(caption.End))
+		builder.WriteString("\n")
+		// A blank line would end the cue early.
+		builder.WriteString(strings.ReplaceAll(caption.Text, "\n\n", "\n"))
+		builder.WriteString("\n\n")
+	}
+	return builder.String()
+}
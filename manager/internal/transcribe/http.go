@@ -0,0 +1,91 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/subtitles"
+	"ai-things/manager-go/internal/utils"
+)
+
+// HTTPEngine posts the audio to an OpenAI-compatible transcription endpoint
+// (e.g. http://whisper:8000/v1/audio/transcriptions) asking for an srt response.
+type HTTPEngine struct {
+	URL      string
+	Model    string
+	APIKey   string
+	Language string
+	Timeout  time.Duration
+}
+
+func (e HTTPEngine) Name() string { return "http" }
+
+func (e HTTPEngine) Transcribe(ctx context.Context, req Request) ([]subtitles.Caption, error) {
+	file, err := os.Open(req.AudioPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filepath.Base(req.AudioPath))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, err
+	}
+	fields := map[string]string{
+		"response_format": "srt",
+		"model":           e.Model,
+		"language":        e.Language,
+	}
+	for key, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := form.WriteField(key, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, &body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", form.FormDataContentType())
+	if e.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+e.APIKey)
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Minute
+	}
+	utils.Debug("transcribe http", "url", e.URL, "model", e.Model, "content_id", req.ContentID)
+	resp, err := (&http.Client{Timeout: timeout}).Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("transcription server status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return parseTranscript(string(data))
+}
@@ -0,0 +1,53 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"ai-things/manager-go/internal/subtitles"
+	"ai-things/manager-go/internal/utils"
+)
+
+// ScriptEngine runs "<Command> <wav> <content_id>" and reads OutDir/transcription_<id>.srt,
+// which is the contract of podcast/whisper.py.
+type ScriptEngine struct {
+	Command string
+	OutDir  string
+	Hint    string
+}
+
+func (e ScriptEngine) Name() string { return "script" }
+
+func (e ScriptEngine) Transcribe(ctx context.Context, req Request) ([]subtitles.Caption, error) {
+	cmd := fmt.Sprintf("%s %s %d", e.Command, utils.ShellEscape(req.AudioPath), req.ContentID)
+	if _, err := utils.RunCommand(cmd); err != nil {
+		if e.Hint != "" {
+			return nil, fmt.Errorf("subtitle script failed (%q): %w\n\n%s", e.Command, err, e.Hint)
+		}
+		return nil, fmt.Errorf("subtitle script failed (%q): %w", e.Command, err)
+	}
+
+	srtPath := filepath.Join(e.OutDir, fmt.Sprintf("transcription_%d.srt", req.ContentID))
+	data, err := os.ReadFile(srtPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseTranscript(string(data))
+}
+
+// parseTranscript parses an srt produced by a backend, tolerating a few bad blocks.
+func parseTranscript(srt string) ([]subtitles.Caption, error) {
+	captions, err := subtitles.ParseSRT(srt)
+	if len(captions) == 0 {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("transcript has no captions")
+	}
+	if err != nil {
+		utils.Warn("transcript has unparseable blocks", "err", err)
+	}
+	return captions, nil
+}
@@ -0,0 +1,95 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/subtitles"
+)
+
+// Segment is a span of the narration with known text (one TTS sentence).
+type Segment struct {
+	Text  string
+	Start time.Duration
+	End   time.Duration
+}
+
+// Request describes the audio to transcribe plus what we already know about it.
+type Request struct {
+	ContentID int64
+	AudioPath string
+	// Text is the script that was synthesized. Only the tts engine relies on it.
+	Text string
+	// Segments are sentence offsets recorded during TTS (may be empty).
+	Segments []Segment
+	// Duration is the audio length; used to estimate timings when Segments are missing.
+	Duration time.Duration
+	// LeadIn/TailPad are silences padded around the speech, Gap the silence between sentences.
+	LeadIn  time.Duration
+	TailPad time.Duration
+	Gap     time.Duration
+}
+
+// Engine produces captions for a narration.
+type Engine interface {
+	Name() string
+	Transcribe(ctx context.Context, req Request) ([]subtitles.Caption, error)
+}
+
+// Options selects and configures an engine.
+type Options struct {
+	Engine string
+
+	// script
+	ScriptCommand string
+	ScriptOutDir  string
+	// ScriptHint is appended to script failures (how to repair the local python env).
+	ScriptHint string
+
+	// whisper_cpp
+	WhisperCppBinary string
+	WhisperCppModel  string
+
+	// http
+	HTTPURL     string
+	HTTPModel   string
+	HTTPAPIKey  string
+	HTTPTimeout time.Duration
+
+	Language string
+}
+
+// New returns the engine named by opts.Engine:
+//   - script (default): the whisper.py style command ("<command> <wav> <content_id>" writing an srt file)
+//   - whisper_cpp:      a local whisper.cpp binary (whisper-cli)
+//   - http:             an OpenAI-compatible /v1/audio/transcriptions server
+//   - tts:              no speech recognition; timings come from the TTS sentence offsets
+func New(opts Options) (Engine, error) {
+	switch strings.ToLower(strings.TrimSpace(opts.Engine)) {
+	case "", "script":
+		if strings.TrimSpace(opts.ScriptCommand) == "" {
+			return nil, fmt.Errorf("transcribe engine script: missing command (set paths.subtitle_script)")
+		}
+		return ScriptEngine{Command: opts.ScriptCommand, OutDir: opts.ScriptOutDir, Hint: opts.ScriptHint}, nil
+	case "whisper_cpp", "whisper.cpp":
+		if strings.TrimSpace(opts.WhisperCppModel) == "" {
+			return nil, fmt.Errorf("transcribe engine whisper_cpp: missing model (set transcribe.whisper_cpp_model)")
+		}
+		binary := opts.WhisperCppBinary
+		if binary == "" {
+			binary = "whisper-cli"
+		}
+		return WhisperCppEngine{Binary: binary, Model: opts.WhisperCppModel, Language: opts.Language}, nil
+	case "http":
+		if strings.TrimSpace(opts.HTTPURL) == "" {
+			return nil, fmt.Errorf("transcribe engine http: missing url (set transcribe.http_url)")
+		}
+		return HTTPEngine{URL: opts.HTTPURL, Model: opts.HTTPModel, APIKey: opts.HTTPAPIKey, Language: opts.Language, Timeout: opts.HTTPTimeout}, nil
+	case "tts", "fake":
+		return TTSEngine{}, nil
+	default:
+		return nil, fmt.Errorf("unknown transcribe engine %q", opts.Engine)
+	}
+}
@@ -0,0 +1,92 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"ai-things/manager-go/internal/subtitles"
+)
+
+// TTSEngine skips speech recognition: the narration was synthesized from a known script, so the
+// captions are the script sentences. Timings come from the sentence offsets recorded during TTS
+// when available, otherwise they are estimated by spreading the sentences over the audio in
+// proportion to their length (accounting for the padding and inter-sentence silences).
+type TTSEngine struct{}
+
+func (e TTSEngine) Name() string { return "tts" }
+
+func (e TTSEngine) Transcribe(ctx context.Context, req Request) ([]subtitles.Caption, error) {
+	if len(req.Segments) > 0 {
+		captions := make([]subtitles.Caption, 0, len(req.Segments))
+		for _, s := range req.Segments {
+			if strings.TrimSpace(s.Text) == "" {
+				continue
+			}
+			captions = append(captions, subtitles.Caption{Start: s.Start, End: s.End, Text: strings.TrimSpace(s.Text)})
+		}
+		if len(captions) > 0 {
+			return captions, nil
+		}
+	}
+	return EstimateSegments(req)
+}
+
+// EstimateSegments spreads the script sentences over the speech part of the audio.
+func EstimateSegments(req Request) ([]subtitles.Caption, error) {
+	sentences := SplitSentences(req.Text)
+	if len(sentences) == 0 {
+		return nil, errors.New("no text to derive captions from")
+	}
+	if req.Duration <= 0 {
+		return nil, errors.New("audio duration unknown")
+	}
+
+	speech := req.Duration - req.LeadIn - req.TailPad - time.Duration(len(sentences)-1)*req.Gap
+	if speech <= 0 {
+		speech = req.Duration
+		req.LeadIn, req.Gap = 0, 0
+	}
+	totalChars := 0
+	for _, s := range sentences {
+		totalChars += utf8.RuneCountInString(s)
+	}
+
+	captions := make([]subtitles.Caption, 0, len(sentences))
+	at := req.LeadIn
+	for _, s := range sentences {
+		length := time.Duration(float64(speech) * float64(utf8.RuneCountInString(s)) / float64(totalChars))
+		captions = append(captions, subtitles.Caption{Start: at, End: at + length, Text: s})
+		at += length + req.Gap
+	}
+	return captions, nil
+}
+
+// SplitSentences splits text on sentence punctuation followed by whitespace and on line breaks,
+// which is close to how the TTS engine segments its input.
+func SplitSentences(text string) []string {
+	var sentences []string
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(strings.TrimSpace(line))
+		start := 0
+		for i := 0; i < len(runes); i++ {
+			if !strings.ContainsRune(".!?", runes[i]) {
+				continue
+			}
+			if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+				continue
+			}
+			if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+				sentences = append(sentences, s)
+			}
+			start = i + 1
+		}
+		if s := strings.TrimSpace(string(runes[start:])); s != "" {
+			sentences = append(sentences, s)
+		}
+	}
+	return sentences
+}
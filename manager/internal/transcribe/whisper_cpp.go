@@ -0,0 +1,58 @@
+package transcribe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"ai-things/manager-go/internal/subtitles"
+	"ai-things/manager-go/internal/utils"
+)
+
+// WhisperCppEngine runs a whisper.cpp binary. whisper.cpp only accepts 16 kHz mono wav, so the
+// narration is resampled into a temp dir first.
+type WhisperCppEngine struct {
+	Binary   string
+	Model    string
+	Language string
+}
+
+func (e WhisperCppEngine) Name() string { return "whisper_cpp" }
+
+func (e WhisperCppEngine) Transcribe(ctx context.Context, req Request) ([]subtitles.Caption, error) {
+	dir, err := os.MkdirTemp("", fmt.Sprintf("whisper-%d-", req.ContentID))
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.wav")
+	cmd := fmt.Sprintf("ffmpeg -y -i %s -ar 16000 -ac 1 -c:a pcm_s16le %s", utils.ShellEscape(req.AudioPath), utils.ShellEscape(input))
+	if _, err := utils.RunCommand(cmd); err != nil {
+		return nil, err
+	}
+
+	language := e.Language
+	if language == "" {
+		language = "en"
+	}
+	outBase := filepath.Join(dir, "transcript")
+	cmd = fmt.Sprintf(
+		"%s -m %s -f %s -l %s -osrt -of %s",
+		utils.ShellEscape(e.Binary),
+		utils.ShellEscape(e.Model),
+		utils.ShellEscape(input),
+		utils.ShellEscape(language),
+		utils.ShellEscape(outBase),
+	)
+	if _, err := utils.RunCommand(cmd); err != nil {
+		return nil, fmt.Errorf("whisper.cpp failed: %w", err)
+	}
+
+	data, err := os.ReadFile(outBase + ".srt")
+	if err != nil {
+		return nil, err
+	}
+	return parseTranscript(string(data))
+}
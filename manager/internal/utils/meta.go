@@ -111,6 +111,9 @@ func ExtractTextFromMeta(meta map[string]any) (string, error) {
 	if text, ok := GetString(meta, "gemini_response", "candidates", "0", "content", "parts", "0", "text"); ok && text != "" {
 		return ProcessText(text), nil
 	}
+	if text, ok := GetString(meta, "llm_response", "text"); ok && text != "" {
+		return ProcessText(text), nil
+	}
 	return "", errors.New("text not found in meta")
 }
 
@@ -0,0 +1,92 @@
+package wisdom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ai-things/manager-go/internal/funfact"
+)
+
+// Wisdom is the JSON document the extract_wisdom prompt asks for: the fabric extract-wisdom
+// sections plus a narration script in the fun-fact shape, so it can go down the TTS pipeline.
+type Wisdom struct {
+	Summary         string
+	Ideas           []string
+	Quotes          []string
+	Facts           []string
+	Recommendations []string
+	Script          funfact.FunFact
+}
+
+// Parse decodes a model answer, tolerating text around the JSON object.
+func Parse(text string) (Wisdom, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return Wisdom{}, errors.New("no JSON object in response")
+	}
+	var raw struct {
+		Summary         string          `json:"summary"`
+		Ideas           []string        `json:"ideas"`
+		Quotes          []string        `json:"quotes"`
+		Facts           []string        `json:"facts"`
+		Recommendations []string        `json:"recommendations"`
+		Script          json.RawMessage `json:"script"`
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &raw); err != nil {
+		return Wisdom{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if len(raw.Script) == 0 {
+		return Wisdom{}, errors.New("script is missing")
+	}
+	script, err := funfact.Parse(string(raw.Script))
+	if err != nil {
+		return Wisdom{}, fmt.Errorf("script: %w", err)
+	}
+	return Wisdom{
+		Summary:         strings.Join(strings.Fields(raw.Summary), " "),
+		Ideas:           clean(raw.Ideas),
+		Quotes:          clean(raw.Quotes),
+		Facts:           clean(raw.Facts),
+		Recommendations: clean(raw.Recommendations),
+		Script:          script,
+	}, nil
+}
+
+func clean(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if item = strings.Join(strings.Fields(item), " "); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Validate checks the sections and the script (see funfact.FunFact.Validate).
+func (w Wisdom) Validate() error {
+	var errs []error
+	if w.Summary == "" {
+		errs = append(errs, errors.New("summary is empty"))
+	}
+	if len(w.Ideas) == 0 && len(w.Facts) == 0 {
+		errs = append(errs, errors.New("ideas and facts are both empty"))
+	}
+	if err := w.Script.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("script: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// Meta is stored as meta.wisdom; the script goes to meta.funfact like any fun fact.
+func (w Wisdom) Meta() map[string]any {
+	return map[string]any{
+		"summary":         w.Summary,
+		"ideas":           w.Ideas,
+		"quotes":          w.Quotes,
+		"facts":           w.Facts,
+		"recommendations": w.Recommendations,
+	}
+}
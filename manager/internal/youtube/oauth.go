@@ -0,0 +1,250 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTokenURL is Google's OAuth2 token endpoint.
+const DefaultTokenURL = "https://oauth2.googleapis.com/token"
+
+// DefaultAuthURL is Google's OAuth2 consent page.
+const DefaultAuthURL = "https://accounts.google.com/o/oauth2/auth"
+
+// Scopes are requested by Youtube:Auth: youtube.upload for videos and youtube.force-ssl for
+// caption tracks.
+var Scopes = []string{
+	"https://www.googleapis.com/auth/youtube.upload",
+	"https://www.googleapis.com/auth/youtube.force-ssl",
+}
+
+// OAuthClient is the client id/secret of a Google Cloud OAuth client ("Desktop app").
+type OAuthClient struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+}
+
+// LoadClientSecrets reads a client_secrets.json downloaded from the Google Cloud console (the
+// file the Python upload script uses).
+func LoadClientSecrets(path string) (OAuthClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return OAuthClient{}, err
+	}
+	type secrets struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		AuthURI      string `json:"auth_uri"`
+		TokenURI     string `json:"token_uri"`
+	}
+	var file struct {
+		Installed *secrets `json:"installed"`
+		Web       *secrets `json:"web"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return OAuthClient{}, fmt.Errorf("client secrets %s: %w", path, err)
+	}
+	s := file.Installed
+	if s == nil {
+		s = file.Web
+	}
+	if s == nil || s.ClientID == "" {
+		return OAuthClient{}, fmt.Errorf("client secrets %s: no installed or web client", path)
+	}
+	return OAuthClient{ClientID: s.ClientID, ClientSecret: s.ClientSecret, AuthURL: s.AuthURI, TokenURL: s.TokenURI}, nil
+}
+
+// Token is an OAuth2 token as stored in the token file.
+type Token struct {
+	AccessToken  string    `json:"access_token,omitempty"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+}
+
+// LoadToken reads a token file written by SaveToken.
+func LoadToken(path string) (Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Token{}, err
+	}
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return Token{}, fmt.Errorf("token %s: %w", path, err)
+	}
+	if token.RefreshToken == "" {
+		return Token{}, fmt.Errorf("token %s: refresh_token missing (run Youtube:Auth)", path)
+	}
+	return token, nil
+}
+
+// SaveToken writes the token file readable by the owner only.
+func SaveToken(path string, token Token) error {
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// TokenSource hands out access tokens, refreshing them with the refresh token shortly before
+// they expire. It is safe for concurrent use.
+type TokenSource struct {
+	Client OAuthClient
+	HTTP   *http.Client
+	// Save, if set, is called with the refreshed token so it can be persisted.
+	Save func(Token) error
+
+	mu    sync.Mutex
+	token Token
+}
+
+// NewTokenSource returns a TokenSource starting from token.
+func NewTokenSource(client OAuthClient, token Token) *TokenSource {
+	return &TokenSource{Client: client, token: token}
+}
+
+// expiryMargin refreshes tokens this long before they expire.
+const expiryMargin = time.Minute
+
+// AccessToken returns a valid access token.
+func (s *TokenSource) AccessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.AccessToken != "" && time.Until(s.token.Expiry) > expiryMargin {
+		return s.token.AccessToken, nil
+	}
+	if s.token.RefreshToken == "" {
+		return "", errors.New("youtube oauth: no refresh token (run Youtube:Auth)")
+	}
+	refreshed, err := s.Client.exchange(ctx, s.HTTP, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.token.RefreshToken},
+	})
+	if err != nil {
+		return "", err
+	}
+	// Google normally keeps the refresh token; keep ours unless a new one is issued.
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = s.token.RefreshToken
+	}
+	if refreshed.Scope == "" {
+		refreshed.Scope = s.token.Scope
+	}
+	s.token = refreshed
+	if s.Save != nil {
+		if err := s.Save(refreshed); err != nil {
+			return "", err
+		}
+	}
+	return refreshed.AccessToken, nil
+}
+
+// Invalidate drops the cached access token so the next call refreshes it (after a 401).
+func (s *TokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token.AccessToken = ""
+}
+
+// AuthCodeURL is the consent page to open in a browser. Google redirects to redirectURI with a
+// code parameter once access is granted.
+func (c OAuthClient) AuthCodeURL(redirectURI string) string {
+	authURL := c.AuthURL
+	if authURL == "" {
+		authURL = DefaultAuthURL
+	}
+	query := url.Values{
+		"client_id":     {c.ClientID},
+		"redirect_uri":  {redirectURI},
+		"response_type": {"code"},
+		"scope":         {strings.Join(Scopes, " ")},
+		"access_type":   {"offline"},
+		"prompt":        {"consent"},
+	}
+	return authURL + "?" + query.Encode()
+}
+
+// Exchange trades an authorization code from the consent page for a token.
+func (c OAuthClient) Exchange(ctx context.Context, httpClient *http.Client, code, redirectURI string) (Token, error) {
+	token, err := c.exchange(ctx, httpClient, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	})
+	if err != nil {
+		return Token{}, err
+	}
+	if token.RefreshToken == "" {
+		return Token{}, errors.New("youtube oauth: no refresh token returned (revoke the app's access and retry)")
+	}
+	return token, nil
+}
+
+func (c OAuthClient) exchange(ctx context.Context, httpClient *http.Client, form url.Values) (Token, error) {
+	tokenURL := c.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	form.Set("client_id", c.ClientID)
+	form.Set("client_secret", c.ClientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Token{}, err
+	}
+	var payload struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Scope            string `json:"scope"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil && resp.StatusCode == http.StatusOK {
+		return Token{}, fmt.Errorf("youtube oauth: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || payload.AccessToken == "" {
+		if payload.Error != "" {
+			return Token{}, fmt.Errorf("youtube oauth status=%d: %s %s", resp.StatusCode, payload.Error, payload.ErrorDescription)
+		}
+		return Token{}, fmt.Errorf("youtube oauth status=%d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return Token{
+		AccessToken:  payload.AccessToken,
+		RefreshToken: payload.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second),
+		Scope:        payload.Scope,
+	}, nil
+}
@@ -0,0 +1,176 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tokenServer is a fake OAuth token endpoint answering with the given expires_in.
+func tokenServer(t *testing.T, expiresIn int, forms *[]url.Values) *httptest.Server {
+	n := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("token form: %v", err)
+		}
+		*forms = append(*forms, r.PostForm)
+		switch {
+		case r.PostForm.Get("refresh_token") == "revoked" || r.PostForm.Get("code") == "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`)
+		case r.PostForm.Get("grant_type") == "authorization_code":
+			fmt.Fprintf(w, `{"access_token":"access-0","refresh_token":"refresh","expires_in":%d,"scope":"upload"}`, expiresIn)
+		default:
+			n++
+			fmt.Fprintf(w, `{"access_token":"access-%d","expires_in":%d}`, n, expiresIn)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTokenSourceRefresh(t *testing.T) {
+	var forms []url.Values
+	srv := tokenServer(t, 3600, &forms)
+	var saved []Token
+	tokens := NewTokenSource(
+		OAuthClient{ClientID: "client", ClientSecret: "secret", TokenURL: srv.URL},
+		Token{RefreshToken: "refresh", Scope: "upload"},
+	)
+	tokens.Save = func(token Token) error {
+		saved = append(saved, token)
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		token, err := tokens.AccessToken(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "access-1" {
+			t.Fatalf("token = %q, want the cached access-1", token)
+		}
+	}
+	if len(forms) != 1 {
+		t.Fatalf("token requests = %d, want 1", len(forms))
+	}
+	form := forms[0]
+	if form.Get("grant_type") != "refresh_token" || form.Get("refresh_token") != "refresh" || form.Get("client_id") != "client" || form.Get("client_secret") != "secret" {
+		t.Errorf("form = %v", form)
+	}
+	if len(saved) != 1 || saved[0].RefreshToken != "refresh" || saved[0].Scope != "upload" || time.Until(saved[0].Expiry) < 59*time.Minute {
+		t.Errorf("saved = %+v", saved)
+	}
+
+	tokens.Invalidate()
+	if token, err := tokens.AccessToken(context.Background()); err != nil || token != "access-2" {
+		t.Errorf("after Invalidate: %q, %v", token, err)
+	}
+}
+
+func TestTokenSourceRefreshesNearExpiry(t *testing.T) {
+	var forms []url.Values
+	// Tokens living less than expiryMargin are refreshed on every call.
+	srv := tokenServer(t, 30, &forms)
+	tokens := NewTokenSource(OAuthClient{TokenURL: srv.URL}, Token{RefreshToken: "refresh"})
+	for i := 1; i <= 2; i++ {
+		if token, err := tokens.AccessToken(context.Background()); err != nil || token != fmt.Sprintf("access-%d", i) {
+			t.Fatalf("call %d: %q, %v", i, token, err)
+		}
+	}
+}
+
+func TestTokenSourceRefreshErrors(t *testing.T) {
+	var forms []url.Values
+	srv := tokenServer(t, 3600, &forms)
+
+	tokens := NewTokenSource(OAuthClient{TokenURL: srv.URL}, Token{RefreshToken: "revoked"})
+	_, err := tokens.AccessToken(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("err = %v, want invalid_grant", err)
+	}
+	if retryable(&authError{err: err}) {
+		t.Error("a failed refresh must not be retried")
+	}
+
+	if _, err := NewTokenSource(OAuthClient{TokenURL: srv.URL}, Token{}).AccessToken(context.Background()); err == nil {
+		t.Error("want an error without a refresh token")
+	}
+}
+
+func TestExchange(t *testing.T) {
+	var forms []url.Values
+	srv := tokenServer(t, 3600, &forms)
+	client := OAuthClient{ClientID: "client", ClientSecret: "secret", TokenURL: srv.URL}
+
+	token, err := client.Exchange(context.Background(), nil, "code", "http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-0" || token.RefreshToken != "refresh" || token.Scope != "upload" {
+		t.Errorf("token = %+v", token)
+	}
+	if form := forms[0]; form.Get("code") != "code" || form.Get("redirect_uri") != "http://localhost" {
+		t.Errorf("form = %v", form)
+	}
+	if _, err := client.Exchange(context.Background(), nil, "bad", "http://localhost"); err == nil {
+		t.Error("want an error for a rejected code")
+	}
+
+	consent, err := url.Parse(client.AuthCodeURL("http://localhost"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := consent.Query()
+	if query.Get("access_type") != "offline" || query.Get("client_id") != "client" || query.Get("scope") != strings.Join(Scopes, " ") {
+		t.Errorf("consent url = %s", consent)
+	}
+}
+
+func TestClientSecretsAndTokenFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, kind := range []string{"installed", "web"} {
+		path := filepath.Join(dir, kind+".json")
+		content := fmt.Sprintf(`{%q:{"client_id":"id","client_secret":"secret","token_uri":"https://example.com/token"}}`, kind)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		client, err := LoadClientSecrets(path)
+		if err != nil || client.ClientID != "id" || client.ClientSecret != "secret" || client.TokenURL != "https://example.com/token" {
+			t.Errorf("%s: %+v, %v", kind, client, err)
+		}
+	}
+	empty := filepath.Join(dir, "empty.json")
+	os.WriteFile(empty, []byte(`{}`), 0o600)
+	if _, err := LoadClientSecrets(empty); err == nil {
+		t.Error("want an error for secrets without a client")
+	}
+
+	path := filepath.Join(dir, "nested", "token.json")
+	want := Token{AccessToken: "a", RefreshToken: "r", Expiry: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), Scope: "s"}
+	if err := SaveToken(path, want); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("token file mode = %v, %v", info.Mode(), err)
+	}
+	got, err := LoadToken(path)
+	if err != nil || got != want {
+		t.Errorf("LoadToken = %+v, %v", got, err)
+	}
+
+	if err := SaveToken(path, Token{AccessToken: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadToken(path); err == nil {
+		t.Error("want an error for a token without refresh_token")
+	}
+}
@@ -0,0 +1,487 @@
+package youtube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-things/manager-go/internal/utils"
+)
+
+// DefaultUploadBaseURL is the media upload root of the YouTube Data API v3.
+const DefaultUploadBaseURL = "https://www.googleapis.com/upload/youtube/v3"
+
+const (
+	// Resumable upload chunks must be a multiple of 256 KiB (except the last one).
+	chunkQuantum     = 256 << 10
+	defaultChunkSize = 8 << 20
+	defaultRetries   = 10
+	maxRetryDelay    = time.Minute
+	// An 8 MiB chunk takes about 80s at 100 KB/s.
+	defaultRequestTimeout = 10 * time.Minute
+
+	// YouTube rejects longer titles (100) and descriptions (5000); keep some headroom on the latter.
+	maxTitleChars       = 100
+	maxDescriptionChars = 4500
+)
+
+// Video is the metadata of an upload.
+type Video struct {
+	Title       string
+	Description string
+	// CategoryID is a numeric video category, e.g. "27" (Education).
+	CategoryID string
+	Tags       []string
+	// PrivacyStatus is public, private or unlisted.
+	PrivacyStatus string
+	MadeForKids   bool
+}
+
+// UploadResult describes a finished upload.
+type UploadResult struct {
+	VideoID string
+	// UploadStatus is status.uploadStatus of the created video (usually "uploaded").
+	UploadStatus string
+	Bytes        int64
+	Chunks       int
+	// Retries counts every retry of the upload, including those reset by later progress.
+	Retries  int
+	Duration time.Duration
+}
+
+// UploadVideo uploads the file at path with the resumable protocol: the file is sent in
+// Client.ChunkSize pieces, and after a network error, 429 or 5xx the upload resumes from the
+// offset the server confirms, with exponential backoff. Client.MaxRetries bounds consecutive
+// retries; the count starts over whenever the server confirms more bytes, so a long upload on a
+// flaky link keeps going while it makes progress. An expired upload session starts over.
+func (c *Client) UploadVideo(ctx context.Context, path string, video Video) (UploadResult, error) {
+	started := time.Now()
+	file, err := os.Open(path)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return UploadResult{}, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return UploadResult{}, fmt.Errorf("youtube upload: %s is empty", path)
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "video/*"
+	}
+
+	result := UploadResult{Bytes: size}
+	var session string
+	var offset, confirmed int64
+	attempts := 0
+	// After a failed chunk the server may have kept part of it; ask before sending more.
+	needStatus := false
+	chunkSize := c.chunkSize()
+	logged := -1
+	for {
+		var done *videoResource
+		var err error
+		switch {
+		case session == "":
+			session, err = c.startUpload(ctx, video, size, contentType)
+			offset, confirmed = 0, 0
+		case needStatus:
+			offset, done, err = c.uploadStatus(ctx, session, size)
+			if err == nil {
+				needStatus = false
+			}
+		default:
+			end := min(offset+chunkSize, size)
+			offset, done, err = c.putChunk(ctx, session, io.NewSectionReader(file, offset, end-offset), offset, end, size)
+			if err == nil {
+				result.Chunks++
+			}
+		}
+
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone) && session != "" {
+				session, needStatus = "", false
+				err = errors.New("upload session expired; starting over")
+			} else if session != "" {
+				needStatus = true
+			}
+			if err := c.backoff(ctx, err, &attempts, "youtube upload retry", "path", path, "offset", offset); err != nil {
+				return result, err
+			}
+			result.Retries++
+			continue
+		}
+		if offset > confirmed {
+			confirmed = offset
+			attempts = 0
+		}
+		if done != nil {
+			if done.ID == "" {
+				return result, fmt.Errorf("youtube upload: response without video id (status %s)", done.Status.UploadStatus)
+			}
+			if done.Status.UploadStatus == "rejected" || done.Status.UploadStatus == "failed" {
+				return result, fmt.Errorf("youtube upload %s %s: %s%s", done.ID, done.Status.UploadStatus, done.Status.RejectionReason, done.Status.FailureReason)
+			}
+			result.VideoID = done.ID
+			result.UploadStatus = done.Status.UploadStatus
+			result.Duration = time.Since(started)
+			utils.Info("youtube upload done", "path", path, "video_id", result.VideoID, "bytes", size, "chunks", result.Chunks, "retries", result.Retries, "duration", result.Duration.Round(time.Second))
+			return result, nil
+		}
+		if percent := int(offset * 100 / size); percent/10 != logged/10 {
+			logged = percent
+			utils.Info("youtube upload progress", "path", path, "percent", percent, "bytes", offset, "total", size)
+		}
+	}
+}
+
+// videoResource is the part of a videos resource the uploader reads.
+type videoResource struct {
+	ID     string `json:"id"`
+	Status struct {
+		UploadStatus    string `json:"uploadStatus"`
+		FailureReason   string `json:"failureReason"`
+		RejectionReason string `json:"rejectionReason"`
+	} `json:"status"`
+}
+
+// startUpload opens a resumable upload session and returns its URL.
+func (c *Client) startUpload(ctx context.Context, video Video, size int64, contentType string) (string, error) {
+	privacy := video.PrivacyStatus
+	if privacy == "" {
+		privacy = "private"
+	}
+	snippet := map[string]any{
+		"title":       SanitizeTitle(video.Title),
+		"description": SanitizeDescription(video.Description),
+		"categoryId":  video.CategoryID,
+	}
+	if len(video.Tags) > 0 {
+		snippet["tags"] = video.Tags
+	}
+	body := map[string]any{
+		"snippet": snippet,
+		"status": map[string]any{
+			"privacyStatus":           privacy,
+			"selfDeclaredMadeForKids": video.MadeForKids,
+		},
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	query := url.Values{"uploadType": {"resumable"}, "part": {"snippet,status"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.uploadBaseURL()+"/videos?"+query.Encode(), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	req.Header.Set("X-Upload-Content-Type", contentType)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", parseAPIError(resp.StatusCode, respBody)
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", errors.New("youtube upload: no session url in response")
+	}
+	return location, nil
+}
+
+// putChunk sends bytes [start, end) and returns the next offset, or the video once the upload
+// is complete.
+func (c *Client) putChunk(ctx context.Context, session string, chunk io.Reader, start, end, size int64) (int64, *videoResource, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, chunk)
+	if err != nil {
+		return start, nil, err
+	}
+	req.ContentLength = end - start
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, size))
+	return c.sessionResponse(ctx, req, start)
+}
+
+// uploadStatus asks how many bytes the server has stored for the session.
+func (c *Client) uploadStatus(ctx context.Context, session string, size int64) (int64, *videoResource, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	return c.sessionResponse(ctx, req, 0)
+}
+
+// sessionResponse interprets a resumable session answer: 308 with the stored Range, or 200/201
+// with the created video.
+func (c *Client) sessionResponse(ctx context.Context, req *http.Request, offset int64) (int64, *videoResource, error) {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return offset, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return offset, nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusPermanentRedirect:
+		return storedBytes(resp.Header.Get("Range")), nil, nil
+	case http.StatusOK, http.StatusCreated:
+		var video videoResource
+		if err := json.Unmarshal(body, &video); err != nil {
+			return offset, nil, fmt.Errorf("youtube upload: %w", err)
+		}
+		return offset, &video, nil
+	default:
+		return offset, nil, parseAPIError(resp.StatusCode, body)
+	}
+}
+
+// storedBytes reads the "bytes=0-N" Range header of a 308; no header means nothing is stored.
+func storedBytes(header string) int64 {
+	_, last, ok := strings.Cut(strings.TrimPrefix(header, "bytes="), "-")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n + 1
+}
+
+// InsertCaption attaches an SRT caption track to a video and returns the caption id.
+func (c *Client) InsertCaption(ctx context.Context, videoID, language, name string, srt []byte) (string, error) {
+	metadata, err := json.Marshal(map[string]any{
+		"snippet": map[string]any{"videoId": videoID, "language": language, "name": name, "isDraft": false},
+	})
+	if err != nil {
+		return "", err
+	}
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		data        []byte
+	}{
+		{"application/json; charset=UTF-8", metadata},
+		{"application/octet-stream", srt},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(part.data); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	query := url.Values{"uploadType": {"multipart"}, "part": {"snippet"}}
+	retries := 0
+	for {
+		id, err := c.captionResponse(ctx, c.uploadBaseURL()+"/captions?"+query.Encode(), "multipart/related; boundary="+writer.Boundary(), body.Bytes())
+		if err == nil {
+			return id, nil
+		}
+		if err := c.backoff(ctx, err, &retries, "youtube caption retry", "video_id", videoID, "lang", language); err != nil {
+			return "", err
+		}
+	}
+}
+
+func (c *Client) captionResponse(ctx context.Context, endpoint, contentType string, payload []byte) (string, error) {
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", parseAPIError(resp.StatusCode, body)
+	}
+	var caption struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &caption); err != nil {
+		return "", fmt.Errorf("youtube caption: %w", err)
+	}
+	return caption.ID, nil
+}
+
+// do sends an authorized request. A 401 drops the cached access token so a retry refreshes it.
+func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.Tokens == nil {
+		return nil, &authError{err: errors.New("youtube oauth is not configured")}
+	}
+	token, err := c.Tokens.AccessToken(ctx)
+	if err != nil {
+		return nil, &authError{err: err}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.httpClient().Do(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		c.Tokens.Invalidate()
+	}
+	return resp, err
+}
+
+// authError marks missing credentials or a failed token refresh, which retrying does not fix.
+type authError struct{ err error }
+
+func (e *authError) Error() string { return e.err.Error() }
+func (e *authError) Unwrap() error { return e.err }
+
+// retryable reports network errors and 401 (stale token), 408, 429 and 5xx answers.
+func retryable(err error) bool {
+	var authErr *authError
+	if errors.As(err, &authErr) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized, apiErr.StatusCode == http.StatusRequestTimeout,
+			apiErr.StatusCode == http.StatusTooManyRequests, apiErr.StatusCode >= 500:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// backoff sleeps before the next attempt after err, or returns err when it is not retryable or
+// the retries are used up.
+func (c *Client) backoff(ctx context.Context, err error, retries *int, msg string, keyvals ...any) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if !retryable(err) || *retries >= c.maxRetries() {
+		return err
+	}
+	*retries++
+	base := c.RetryDelay
+	if base <= 0 {
+		base = time.Second
+	}
+	delay := min(base<<min(*retries-1, 10), maxRetryDelay)
+	// Full jitter, as in the upload script.
+	delay = time.Duration(rand.Int64N(int64(delay)) + 1)
+	utils.Warn(msg, append(keyvals, "attempt", *retries, "delay", delay.Round(time.Millisecond), "err", err)...)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requestContext bounds one upload request (and reading its answer) by Client.RequestTimeout, so
+// a stalled connection fails that request and is retried instead of hanging the upload.
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.RequestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (c *Client) chunkSize() int64 {
+	if c.ChunkSize <= 0 {
+		return defaultChunkSize
+	}
+	return max((c.ChunkSize+chunkQuantum-1)/chunkQuantum*chunkQuantum, chunkQuantum)
+}
+
+func (c *Client) maxRetries() int {
+	if c.MaxRetries <= 0 {
+		return defaultRetries
+	}
+	return c.MaxRetries
+}
+
+func (c *Client) uploadBaseURL() string {
+	if c.UploadBaseURL == "" {
+		return DefaultUploadBaseURL
+	}
+	return strings.TrimRight(c.UploadBaseURL, "/")
+}
+
+// SanitizeTitle drops control characters and truncates to YouTube's title limit.
+func SanitizeTitle(title string) string {
+	return truncate(sanitizeText(title), maxTitleChars)
+}
+
+// SanitizeDescription drops control characters and truncates to YouTube's description limit.
+func SanitizeDescription(description string) string {
+	return truncate(sanitizeText(description), maxDescriptionChars)
+}
+
+func sanitizeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\r", "\n")
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r < 32 || r == 127 {
+			return -1
+		}
+		return r
+	}, value))
+}
+
+func truncate(value string, maxChars int) string {
+	runes := []rune(value)
+	if len(runes) <= maxChars {
+		return value
+	}
+	if maxChars <= 3 {
+		return string(runes[:maxChars])
+	}
+	return string(runes[:maxChars-3]) + "..."
+}
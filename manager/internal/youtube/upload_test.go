@@ -0,0 +1,506 @@
+package youtube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeYouTube implements the parts of the upload API the client uses: the token endpoint, the
+// resumable videos.insert protocol and captions.insert.
+type fakeYouTube struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu sync.Mutex
+	// token is the access token the API accepts; the token endpoint hands out a new one.
+	token     string
+	refreshes int
+	// session is the id of the live upload session; older ones answer 404.
+	session  int
+	total    int64
+	stored   []byte
+	metadata map[string]any
+	puts     []string
+	// chunk, if set, can replace the answer to the n-th chunk PUT (1-based, across sessions): it
+	// returns the status to send (0 to accept the chunk normally) and how many bytes of the chunk
+	// to keep.
+	chunk    func(n int, data []byte) (status int, keep int)
+	chunks   int
+	captions []fakeCaption
+	// captionStatus, if set, answers the n-th caption insert with that status.
+	captionStatus func(n int) int
+}
+
+type fakeCaption struct {
+	Snippet map[string]any
+	Body    string
+}
+
+func newFakeYouTube(t *testing.T) *fakeYouTube {
+	f := &fakeYouTube{t: t, token: "good"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", f.handleToken)
+	mux.HandleFunc("/upload/videos", f.handleStart)
+	mux.HandleFunc("/session/", f.handleSession)
+	mux.HandleFunc("/upload/captions", f.handleCaption)
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+	return f
+}
+
+// client returns a Client for the fake server whose token source starts with accessToken.
+func (f *fakeYouTube) client(accessToken string) *Client {
+	tokens := NewTokenSource(
+		OAuthClient{ClientID: "client", ClientSecret: "secret", TokenURL: f.srv.URL + "/token"},
+		Token{AccessToken: accessToken, RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+	)
+	return &Client{
+		UploadBaseURL: f.srv.URL + "/upload",
+		HTTP:          f.srv.Client(),
+		Tokens:        tokens,
+		ChunkSize:     chunkQuantum,
+		RetryDelay:    time.Millisecond,
+	}
+}
+
+func (f *fakeYouTube) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Authorization") != "Bearer "+f.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"code":401,"message":"Invalid Credentials","errors":[{"reason":"authError"}]}}`)
+		return false
+	}
+	return true
+}
+
+func (f *fakeYouTube) handleToken(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh" {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Bad Request"}`)
+		return
+	}
+	f.refreshes++
+	f.token = fmt.Sprintf("fresh-%d", f.refreshes)
+	fmt.Fprintf(w, `{"access_token":%q,"expires_in":3599,"token_type":"Bearer"}`, f.token)
+}
+
+func (f *fakeYouTube) handleStart(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.authorized(w, r) {
+		return
+	}
+	if r.URL.Query().Get("uploadType") != "resumable" || r.URL.Query().Get("part") != "snippet,status" {
+		f.t.Errorf("start query = %s", r.URL.RawQuery)
+	}
+	f.metadata = nil
+	if err := json.NewDecoder(r.Body).Decode(&f.metadata); err != nil {
+		f.t.Errorf("start body: %v", err)
+	}
+	f.total, _ = strconv.ParseInt(r.Header.Get("X-Upload-Content-Length"), 10, 64)
+	f.session++
+	f.stored = nil
+	w.Header().Set("Location", fmt.Sprintf("%s/session/%d", f.srv.URL, f.session))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeYouTube) handleSession(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.authorized(w, r) {
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	contentRange := r.Header.Get("Content-Range")
+	f.puts = append(f.puts, contentRange)
+	if r.URL.Path != fmt.Sprintf("/session/%d", f.session) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !strings.HasPrefix(contentRange, "bytes */") {
+		var start, end, total int64
+		if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+			f.t.Errorf("Content-Range %q: %v", contentRange, err)
+		}
+		if start != int64(len(f.stored)) || end-start+1 != int64(len(data)) || total != f.total {
+			f.t.Errorf("Content-Range %q with %d bytes, server has %d", contentRange, len(data), len(f.stored))
+		}
+		f.chunks++
+		keep := len(data)
+		if f.chunk != nil {
+			var status int
+			if status, keep = f.chunk(f.chunks, data); status != 0 {
+				f.stored = append(f.stored, data[:keep]...)
+				w.WriteHeader(status)
+				return
+			}
+		}
+		f.stored = append(f.stored, data[:keep]...)
+	}
+
+	if int64(len(f.stored)) == f.total {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"video123","status":{"uploadStatus":"uploaded"}}`)
+		return
+	}
+	if len(f.stored) > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(f.stored)-1))
+	}
+	w.WriteHeader(http.StatusPermanentRedirect)
+}
+
+func (f *fakeYouTube) handleCaption(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.authorized(w, r) {
+		return
+	}
+	if f.captionStatus != nil {
+		if status := f.captionStatus(len(f.captions) + 1); status != 0 {
+			f.captions = append(f.captions, fakeCaption{})
+			w.WriteHeader(status)
+			return
+		}
+	}
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/related" {
+		f.t.Errorf("caption Content-Type = %q", r.Header.Get("Content-Type"))
+		return
+	}
+	reader := multipart.NewReader(r.Body, params["boundary"])
+	var caption fakeCaption
+	part, err := reader.NextPart()
+	if err != nil {
+		f.t.Errorf("caption metadata part: %v", err)
+		return
+	}
+	var metadata struct {
+		Snippet map[string]any `json:"snippet"`
+	}
+	if err := json.NewDecoder(part).Decode(&metadata); err != nil {
+		f.t.Errorf("caption metadata: %v", err)
+	}
+	caption.Snippet = metadata.Snippet
+	part, err = reader.NextPart()
+	if err != nil {
+		f.t.Errorf("caption media part: %v", err)
+		return
+	}
+	body, _ := io.ReadAll(part)
+	caption.Body = string(body)
+	f.captions = append(f.captions, caption)
+	fmt.Fprintf(w, `{"id":"caption%d"}`, len(f.captions))
+}
+
+// writeVideo writes a test file of size bytes and returns its path and content.
+func writeVideo(t *testing.T, size int) (string, []byte) {
+	t.Helper()
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i * 31)
+	}
+	path := filepath.Join(t.TempDir(), "episode.mp4")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, data
+}
+
+func TestUploadVideoChunks(t *testing.T) {
+	fake := newFakeYouTube(t)
+	path, data := writeVideo(t, 3*chunkQuantum+1234)
+
+	result, err := fake.client("good").UploadVideo(context.Background(), path, Video{
+		Title:         "Fun\x00 fact " + strings.Repeat("x", 120),
+		Description:   "line one\r\nline two",
+		CategoryID:    "27",
+		Tags:          []string{"science", "space"},
+		PrivacyStatus: "public",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.VideoID != "video123" || result.UploadStatus != "uploaded" || result.Chunks != 4 || result.Retries != 0 || result.Bytes != int64(len(data)) {
+		t.Errorf("result = %+v", result)
+	}
+	if !bytes.Equal(fake.stored, data) {
+		t.Error("stored bytes differ from the file")
+	}
+	want := []string{
+		fmt.Sprintf("bytes 0-%d/%d", chunkQuantum-1, len(data)),
+		fmt.Sprintf("bytes %d-%d/%d", chunkQuantum, 2*chunkQuantum-1, len(data)),
+		fmt.Sprintf("bytes %d-%d/%d", 2*chunkQuantum, 3*chunkQuantum-1, len(data)),
+		fmt.Sprintf("bytes %d-%d/%d", 3*chunkQuantum, len(data)-1, len(data)),
+	}
+	if fmt.Sprint(fake.puts) != fmt.Sprint(want) {
+		t.Errorf("puts = %v, want %v", fake.puts, want)
+	}
+
+	snippet, _ := fake.metadata["snippet"].(map[string]any)
+	title, _ := snippet["title"].(string)
+	if len([]rune(title)) != maxTitleChars || strings.ContainsRune(title, 0) || !strings.HasSuffix(title, "...") {
+		t.Errorf("title = %q", title)
+	}
+	if snippet["description"] != "line one\nline two" || snippet["categoryId"] != "27" || fmt.Sprint(snippet["tags"]) != "[science space]" {
+		t.Errorf("snippet = %v", snippet)
+	}
+	status, _ := fake.metadata["status"].(map[string]any)
+	if status["privacyStatus"] != "public" || status["selfDeclaredMadeForKids"] != false {
+		t.Errorf("status = %v", status)
+	}
+}
+
+func TestUploadVideoResumesFromConfirmedRange(t *testing.T) {
+	fake := newFakeYouTube(t)
+	// The server keeps only part of the second chunk and says so in the 308 Range header.
+	fake.chunk = func(n int, data []byte) (int, int) {
+		if n == 2 {
+			return 0, 1000
+		}
+		return 0, len(data)
+	}
+	path, data := writeVideo(t, 3*chunkQuantum)
+
+	result, err := fake.client("good").UploadVideo(context.Background(), path, Video{Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fake.stored, data) {
+		t.Error("stored bytes differ from the file")
+	}
+	if want := fmt.Sprintf("bytes %d-%d/%d", chunkQuantum+1000, 2*chunkQuantum+999, len(data)); fake.puts[2] != want {
+		t.Errorf("third put = %q, want %q", fake.puts[2], want)
+	}
+	if result.Retries != 0 || result.Chunks != 4 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestUploadVideoResumesAfterServerError(t *testing.T) {
+	fake := newFakeYouTube(t)
+	// The second chunk fails halfway with a 503; the client must ask for the stored range and
+	// send the rest from there.
+	fake.chunk = func(n int, data []byte) (int, int) {
+		if n == 2 {
+			return http.StatusServiceUnavailable, len(data) / 2
+		}
+		return 0, len(data)
+	}
+	path, data := writeVideo(t, 3*chunkQuantum)
+
+	result, err := fake.client("good").UploadVideo(context.Background(), path, Video{Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fake.stored, data) {
+		t.Error("stored bytes differ from the file")
+	}
+	if fake.puts[2] != fmt.Sprintf("bytes */%d", len(data)) {
+		t.Errorf("put after the 503 = %q, want a status query", fake.puts[2])
+	}
+	if want := fmt.Sprintf("bytes %d-", chunkQuantum+chunkQuantum/2); !strings.HasPrefix(fake.puts[3], want) {
+		t.Errorf("resumed put = %q, want %s...", fake.puts[3], want)
+	}
+	if result.Retries != 1 || fake.session != 1 {
+		t.Errorf("result = %+v, sessions = %d", result, fake.session)
+	}
+}
+
+func TestUploadVideoRestartsExpiredSession(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusGone} {
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			fake := newFakeYouTube(t)
+			fake.chunk = func(n int, data []byte) (int, int) {
+				if n == 2 {
+					return status, 0
+				}
+				return 0, len(data)
+			}
+			path, data := writeVideo(t, 2*chunkQuantum+10)
+
+			result, err := fake.client("good").UploadVideo(context.Background(), path, Video{Title: "t"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fake.session != 2 {
+				t.Errorf("sessions = %d, want 2", fake.session)
+			}
+			if !bytes.Equal(fake.stored, data) {
+				t.Error("stored bytes differ from the file")
+			}
+			if result.Retries != 1 {
+				t.Errorf("result = %+v", result)
+			}
+		})
+	}
+}
+
+func TestUploadVideoRefreshesTokenAfter401(t *testing.T) {
+	fake := newFakeYouTube(t)
+	path, data := writeVideo(t, chunkQuantum)
+
+	client := fake.client("stale")
+	result, err := client.UploadVideo(context.Background(), path, Video{Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.refreshes != 1 || fake.token != "fresh-1" {
+		t.Errorf("refreshes = %d, token = %q", fake.refreshes, fake.token)
+	}
+	if !bytes.Equal(fake.stored, data) || result.Retries != 1 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestUploadVideoRetryBudgetResetsOnProgress(t *testing.T) {
+	fake := newFakeYouTube(t)
+	// Every chunk fails twice before it goes through: 8 retries in total, never more than 2 in a
+	// row.
+	fake.chunk = func(n int, data []byte) (int, int) {
+		if n%3 != 0 {
+			return http.StatusInternalServerError, 0
+		}
+		return 0, len(data)
+	}
+	path, data := writeVideo(t, 4*chunkQuantum)
+
+	client := fake.client("good")
+	client.MaxRetries = 2
+	result, err := client.UploadVideo(context.Background(), path, Video{Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fake.stored, data) || result.Retries != 8 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestUploadVideoGivesUpWithoutProgress(t *testing.T) {
+	fake := newFakeYouTube(t)
+	fake.chunk = func(int, []byte) (int, int) { return http.StatusServiceUnavailable, 0 }
+	path, _ := writeVideo(t, chunkQuantum)
+
+	client := fake.client("good")
+	client.MaxRetries = 3
+	result, err := client.UploadVideo(context.Background(), path, Video{Title: "t"})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want a 503 APIError", err)
+	}
+	if result.Retries != 3 {
+		t.Errorf("retries = %d, want 3", result.Retries)
+	}
+}
+
+func TestUploadVideoDoesNotRetryClientErrors(t *testing.T) {
+	fake := newFakeYouTube(t)
+	fake.chunk = func(int, []byte) (int, int) { return http.StatusBadRequest, 0 }
+	path, _ := writeVideo(t, chunkQuantum)
+
+	result, err := fake.client("good").UploadVideo(context.Background(), path, Video{Title: "t"})
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("err = %v, want a 400 APIError", err)
+	}
+	if result.Retries != 0 || fake.chunks != 1 {
+		t.Errorf("retries = %d, chunks = %d", result.Retries, fake.chunks)
+	}
+}
+
+func TestUploadVideoRetriesStalledRequest(t *testing.T) {
+	fake := newFakeYouTube(t)
+	stalled := make(chan struct{})
+	released := make(chan struct{})
+	fake.chunk = func(n int, data []byte) (int, int) {
+		if n == 1 {
+			// Hold the answer past the client's deadline.
+			fake.mu.Unlock()
+			<-stalled
+			fake.mu.Lock()
+			close(released)
+			return http.StatusServiceUnavailable, 0
+		}
+		return 0, len(data)
+	}
+	path, data := writeVideo(t, chunkQuantum)
+
+	client := fake.client("good")
+	client.RequestTimeout = 50 * time.Millisecond
+	result, err := client.UploadVideo(context.Background(), path, Video{Title: "t"})
+	close(stalled)
+	<-released
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !bytes.Equal(fake.stored, data) || result.Retries == 0 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestInsertCaption(t *testing.T) {
+	fake := newFakeYouTube(t)
+	fake.captionStatus = func(n int) int {
+		if n == 1 {
+			return http.StatusBadGateway
+		}
+		return 0
+	}
+	srt := "1\n00:00:00,000 --> 00:00:01,500\nHola\n"
+
+	id, err := fake.client("good").InsertCaption(context.Background(), "video123", "es", "", []byte(srt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "caption2" || len(fake.captions) != 2 {
+		t.Fatalf("id = %q after %d requests", id, len(fake.captions))
+	}
+	caption := fake.captions[1]
+	if caption.Body != srt {
+		t.Errorf("body = %q", caption.Body)
+	}
+	if caption.Snippet["videoId"] != "video123" || caption.Snippet["language"] != "es" || caption.Snippet["isDraft"] != false {
+		t.Errorf("snippet = %v", caption.Snippet)
+	}
+}
+
+func TestUploadRequiresTokens(t *testing.T) {
+	path, _ := writeVideo(t, 10)
+	client := &Client{UploadBaseURL: "http://127.0.0.1:1"}
+	if _, err := client.UploadVideo(context.Background(), path, Video{Title: "t"}); err == nil {
+		t.Fatal("want an error without OAuth tokens")
+	}
+}
+
+func TestStoredBytes(t *testing.T) {
+	tests := map[string]int64{
+		"":              0,
+		"bytes=0-0":     1,
+		"bytes=0-12345": 12346,
+		"garbage":       0,
+	}
+	for header, want := range tests {
+		if got := storedBytes(header); got != want {
+			t.Errorf("storedBytes(%q) = %d, want %d", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the YouTube Data API v3 root; tests point Client.BaseURL at a fake server.
+const DefaultBaseURL = "https://www.googleapis.com/youtube/v3"
+
+// maxIDsPerRequest is the videos.list id limit.
+const maxIDsPerRequest = 50
+
+// Client calls the YouTube Data API. Read calls (statistics) only need an API key; uploads
+// need OAuth Tokens.
+type Client struct {
+	APIKey        string
+	BaseURL       string
+	UploadBaseURL string
+	HTTP          *http.Client
+	Tokens        *TokenSource
+	// ChunkSize is the resumable upload chunk size, rounded up to 256 KiB (default 8 MiB).
+	ChunkSize int64
+	// MaxRetries bounds consecutive retries of an upload without progress (default 10);
+	// RetryDelay is the first backoff (default 1s), doubling up to a minute.
+	MaxRetries int
+	RetryDelay time.Duration
+	// RequestTimeout bounds each upload request, one chunk included (default 10 minutes).
+	RequestTimeout time.Duration
+}
+
+// NewClient returns a Client for the public API.
+func NewClient(apiKey string) *Client {
+	return &Client{APIKey: apiKey, BaseURL: DefaultBaseURL, UploadBaseURL: DefaultUploadBaseURL, HTTP: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Statistics are the public counters of a video.
+type Statistics struct {
+	ViewCount    int64
+	LikeCount    int64
+	CommentCount int64
+	PublishedAt  *time.Time
+}
+
+// APIError is an error answer from the API.
+type APIError struct {
+	StatusCode int
+	Reason     string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("youtube api status=%d reason=%s: %s", e.StatusCode, e.Reason, e.Message)
+	}
+	return fmt.Sprintf("youtube api status=%d: %s", e.StatusCode, e.Message)
+}
+
+// QuotaExceeded reports a 403 for the project's exhausted daily quota, which retrying today does
+// not fix.
+func QuotaExceeded(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	return apiErr.Reason == "quotaExceeded" || apiErr.Reason == "dailyLimitExceeded"
+}
+
+// VideoStatistics returns the statistics of the given video ids, batched 50 per request. Videos
+// that no longer exist (or are private) are missing from the map.
+func (c *Client) VideoStatistics(ctx context.Context, ids []string) (map[string]Statistics, error) {
+	if c.APIKey == "" {
+		return nil, errors.New("youtube api key is not configured")
+	}
+	out := make(map[string]Statistics, len(ids))
+	for start := 0; start < len(ids); start += maxIDsPerRequest {
+		batch := ids[start:min(start+maxIDsPerRequest, len(ids))]
+		query := url.Values{
+			"part":   {"statistics,snippet"},
+			"id":     {strings.Join(batch, ",")},
+			"fields": {"items(id,snippet/publishedAt,statistics)"},
+			"key":    {c.APIKey},
+		}
+		var resp struct {
+			Items []struct {
+				ID      string `json:"id"`
+				Snippet struct {
+					PublishedAt string `json:"publishedAt"`
+				} `json:"snippet"`
+				Statistics struct {
+					ViewCount    string `json:"viewCount"`
+					LikeCount    string `json:"likeCount"`
+					CommentCount string `json:"commentCount"`
+				} `json:"statistics"`
+			} `json:"items"`
+		}
+		if err := c.getJSON(ctx, "/videos?"+query.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Items {
+			stats := Statistics{
+				ViewCount:    parseCount(item.Statistics.ViewCount),
+				LikeCount:    parseCount(item.Statistics.LikeCount),
+				CommentCount: parseCount(item.Statistics.CommentCount),
+			}
+			if t, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt); err == nil {
+				stats.PublishedAt = &t
+			}
+			out[item.ID] = stats
+		}
+	}
+	return out, nil
+}
+
+// parseCount reads the API's string counters; hidden counts (likes can be disabled) are 0.
+func parseCount(value string) int64 {
+	n, _ := strconv.ParseInt(value, 10, 64)
+	return n
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL()+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseAPIError(resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// parseAPIError decodes the {"error": {"message", "errors": [{"reason"}]}} envelope.
+func parseAPIError(status int, body []byte) error {
+	var envelope struct {
+		Error struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	apiErr := &APIError{StatusCode: status, Message: strings.TrimSpace(string(body))}
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error.Message != "" {
+		apiErr.Message = envelope.Error.Message
+		if len(envelope.Error.Errors) > 0 {
+			apiErr.Reason = envelope.Error.Errors[0].Reason
+		}
+	}
+	return apiErr
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTP == nil {
+		return http.DefaultClient
+	}
+	return c.HTTP
+}
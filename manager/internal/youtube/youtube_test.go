@@ -0,0 +1,103 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVideoStatisticsBatches(t *testing.T) {
+	var batches []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/videos" || r.URL.Query().Get("key") != "k" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		ids := strings.Split(r.URL.Query().Get("id"), ",")
+		batches = append(batches, len(ids))
+		var items []string
+		for _, id := range ids {
+			// v7 no longer exists; v8 hides its likes.
+			switch id {
+			case "v7":
+			case "v8":
+				items = append(items, `{"id":"v8","snippet":{"publishedAt":"2026-10-01T12:00:00Z"},"statistics":{"viewCount":"80"}}`)
+			default:
+				items = append(items, fmt.Sprintf(`{"id":%q,"statistics":{"viewCount":"10","likeCount":"2","commentCount":"1"}}`, id))
+			}
+		}
+		fmt.Fprintf(w, `{"items":[%s]}`, strings.Join(items, ","))
+	}))
+	defer srv.Close()
+
+	ids := make([]string, 120)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("v%d", i)
+	}
+	client := &Client{APIKey: "k", BaseURL: srv.URL}
+	stats, err := client.VideoStatistics(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(batches) != "[50 50 20]" {
+		t.Errorf("batches = %v, want [50 50 20]", batches)
+	}
+	if len(stats) != 119 {
+		t.Errorf("stats = %d, want 119", len(stats))
+	}
+	if _, ok := stats["v7"]; ok {
+		t.Error("missing video v7 is in the result")
+	}
+	v8 := stats["v8"]
+	if v8.ViewCount != 80 || v8.LikeCount != 0 || v8.PublishedAt == nil || v8.PublishedAt.Day() != 1 {
+		t.Errorf("v8 = %+v", v8)
+	}
+	if v0 := stats["v0"]; v0.ViewCount != 10 || v0.LikeCount != 2 || v0.CommentCount != 1 || v0.PublishedAt != nil {
+		t.Errorf("v0 = %+v", v0)
+	}
+}
+
+func TestVideoStatisticsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		reason  string
+		message string
+		quota   bool
+	}{
+		{"quota", 403, `{"error":{"code":403,"message":"The request cannot be completed because you have exceeded your quota.","errors":[{"reason":"quotaExceeded"}]}}`, "quotaExceeded", "The request cannot be completed because you have exceeded your quota.", true},
+		{"forbidden", 403, `{"error":{"message":"API key not valid.","errors":[{"reason":"keyInvalid"}]}}`, "keyInvalid", "API key not valid.", false},
+		{"plain body", 502, "Bad Gateway\n", "", "Bad Gateway", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+
+			client := &Client{APIKey: "k", BaseURL: srv.URL}
+			_, err := client.VideoStatistics(context.Background(), []string{"v1"})
+			apiErr, ok := err.(*APIError)
+			if !ok {
+				t.Fatalf("err = %v, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Reason != tt.reason || apiErr.Message != tt.message {
+				t.Errorf("err = %+v", apiErr)
+			}
+			if QuotaExceeded(err) != tt.quota {
+				t.Errorf("QuotaExceeded = %v, want %v", !tt.quota, tt.quota)
+			}
+		})
+	}
+}
+
+func TestVideoStatisticsRequiresKey(t *testing.T) {
+	if _, err := (&Client{}).VideoStatistics(context.Background(), []string{"v1"}); err == nil {
+		t.Fatal("want an error without an API key")
+	}
+}